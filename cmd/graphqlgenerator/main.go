@@ -0,0 +1,619 @@
+// Command graphqlgenerator generates graphql-go types from GraphQL schema
+// files.
+//
+// Usage:
+//
+//	graphqlgenerator generate [flags] schema.graphql...
+//	graphqlgenerator validate [flags] schema.graphql...
+//	graphqlgenerator scaffold [flags] -o resolvers.go schema.graphql...
+//	graphqlgenerator format [-l] [-d] schema.graphql...
+//	graphqlgenerator print schema.graphql...
+//	graphqlgenerator introspect [flags] schema.graphql...
+//	graphqlgenerator compare [-json] old.graphql new.graphql
+//	graphqlgenerator reverse [-package name] types.go...
+//
+// generate writes the generated Go code to the file named by -o, or to
+// standard output. With -check it instead compares the output file with what
+// it would write, printing a unified diff and failing if they differ, which
+// lets CI catch schema edits that were not regenerated. validate checks that
+// the schema parses and generates without writing anything. scaffold adds a
+// stub to the resolver file named by -o for each resolver method of the
+// schema it lacks, keeping the code already there, and reports methods whose
+// signatures no longer match. format rewrites schema files, or the .graphql
+// files in directories, in canonical layout, keeping comments and
+// descriptions; with -l it instead lists the files whose layout differs and
+// with -d it prints the diffs. print writes the combined schema to standard
+// output in that layout. introspect writes the introspection result of the
+// schema as JSON, to the file named by -o or to standard output, for client
+// code generators. compare lists the changes from an old version of a schema
+// to a new one, each a file or a directory of .graphql files, as breaking,
+// dangerous or safe, and fails if any is breaking; -json writes the report as
+// JSON. reverse reads graphql-go types defined in Go files, generated or
+// written by hand, and prints them as a schema.
+//
+// With -watch, generate keeps running and regenerates the output file
+// whenever the schema files change. It polls the files, so it works on any
+// file system, and reports errors without exiting so the schema can be fixed
+// in place.
+//
+// generate, validate, scaffold and introspect take their settings from a
+// configuration file named by -config, or from graphqlgenerator.json in the
+// current directory when no schema files are given; flags override the
+// configuration.
+//
+// Schema files given to generate, validate, scaffold, print and introspect
+// may also be introspection results in JSON, as servers return them, so that
+// types can be generated for a schema only published that way.
+//
+// Errors are reported as file:line:column: message and make the command exit
+// with status 1, so it can be used from go:generate lines such as
+//
+//	//go:generate graphqlgenerator generate -o schema_gen.go schema.graphql
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vivevincere/graphqlgenerator"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+const usage = `usage: graphqlgenerator <command> [flags] schema.graphql...
+
+commands:
+  generate    generate graphql-go types
+  validate    check that schemas parse and generate
+  scaffold    add stubs for missing resolver methods
+  format      rewrite schemas in canonical layout
+  print       print schemas in canonical layout
+  introspect  write the introspection result of schemas as JSON
+  compare     report breaking changes between two versions of a schema
+  reverse     print the schema of graphql-go types defined in Go files
+`
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	var cmd func([]string, io.Writer, io.Writer) error
+	switch args[0] {
+	case "generate":
+		cmd = runGenerate
+	case "validate":
+		cmd = runValidate
+	case "scaffold":
+		cmd = runScaffold
+	case "format":
+		cmd = runFormat
+	case "print":
+		cmd = runPrint
+	case "introspect":
+		cmd = runIntrospect
+	case "compare":
+		cmd = runCompare
+	case "reverse":
+		cmd = runReverse
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "graphqlgenerator: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+	if err := cmd(args[1:], stdout, stderr); err != nil {
+		if msg := err.Error(); msg != "" {
+			fmt.Fprintln(stderr, msg)
+		}
+		if _, ok := err.(usageError); ok {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// usageError is a problem with the command line rather than the schema.
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+// generatorFlags are the flags configuring the generator.
+type generatorFlags struct {
+	config      string
+	packageName string
+	style       string
+	mode        string
+	header      string
+	templates   string
+	prefix      string
+	suffix      string
+	resolvers   bool
+	scalars     mapFlag
+	imports     mapFlag
+	modelTypes  mapFlag
+}
+
+func (f *generatorFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file; "+graphqlgenerator.ConfigFile+" is used if present and no schema files are given")
+	fs.StringVar(&f.packageName, "package", "", "package name of the generated code, overriding the schema")
+	fs.StringVar(&f.style, "style", "", "output style, var or thunk")
+	fs.StringVar(&f.mode, "mode", "", "output, schema, models or all")
+	fs.StringVar(&f.header, "header", "", "comment text written at the top of the generated file")
+	fs.StringVar(&f.templates, "templates", "", "glob of template files redefining the built in templates")
+	fs.StringVar(&f.prefix, "prefix", "", "prefix of the Go names of the generated values")
+	fs.StringVar(&f.suffix, "suffix", "", "suffix of the Go names of the generated values, such as Type")
+	fs.BoolVar(&f.resolvers, "resolvers", false, "wire the fields to generated resolver interfaces; requires -mode all")
+	fs.Var(&f.scalars, "scalar", "map a GraphQL type to a Go expression, as Name=expr; may be repeated")
+	fs.Var(&f.modelTypes, "model-type", "Go type of model fields of a GraphQL scalar, as Name=type; may be repeated")
+	fs.Var(&f.imports, "import", "import path of a package used in Go expressions, as name=path; may be repeated")
+}
+
+// setup parses the flags of a generating command. It returns the schema
+// files, the output file from the configuration, if any, and the generator
+// options, where flags take precedence over the configuration.
+func (f *generatorFlags) setup(fs *flag.FlagSet, args []string) ([]string, string, []graphqlgenerator.Option, error) {
+	if err := fs.Parse(args); err != nil {
+		// The flag package has already reported the problem.
+		return nil, "", nil, usageError{}
+	}
+	paths := fs.Args()
+	config := f.config
+	if config == "" && len(paths) == 0 {
+		if _, err := os.Stat(graphqlgenerator.ConfigFile); err == nil {
+			config = graphqlgenerator.ConfigFile
+		}
+	}
+	var output string
+	var opts []graphqlgenerator.Option
+	if config != "" {
+		c, err := graphqlgenerator.LoadConfig(config)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if len(paths) == 0 {
+			if paths, err = c.Paths(); err != nil {
+				return nil, "", nil, fmt.Errorf("%s: %v", config, err)
+			}
+		}
+		output = c.OutputPath()
+		if opts, err = c.Options(); err != nil {
+			return nil, "", nil, fmt.Errorf("%s: %v", config, err)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, "", nil, usageError{fmt.Sprintf("graphqlgenerator %s: no schema files given", fs.Name())}
+	}
+	flagOpts, err := f.options()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return paths, output, append(opts, flagOpts...), nil
+}
+
+func (f *generatorFlags) options() ([]graphqlgenerator.Option, error) {
+	var opts []graphqlgenerator.Option
+	if f.packageName != "" {
+		opts = append(opts, graphqlgenerator.WithPackageName(f.packageName))
+	}
+	if f.style != "" {
+		style, err := graphqlgenerator.ParseStyle(f.style)
+		if err != nil {
+			return nil, usageError{err.Error()}
+		}
+		opts = append(opts, graphqlgenerator.WithStyle(style))
+	}
+	if f.mode != "" {
+		mode, err := graphqlgenerator.ParseMode(f.mode)
+		if err != nil {
+			return nil, usageError{err.Error()}
+		}
+		opts = append(opts, graphqlgenerator.WithMode(mode))
+	}
+	if f.header != "" {
+		opts = append(opts, graphqlgenerator.WithHeader(f.header))
+	}
+	if f.prefix != "" {
+		opts = append(opts, graphqlgenerator.WithNamePrefix(f.prefix))
+	}
+	if f.suffix != "" {
+		opts = append(opts, graphqlgenerator.WithNameSuffix(f.suffix))
+	}
+	if f.resolvers {
+		opts = append(opts, graphqlgenerator.WithResolvers())
+	}
+	if f.templates != "" {
+		t, err := graphqlgenerator.DefaultTemplates().ParseGlob(f.templates)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, graphqlgenerator.WithTemplates(t))
+	}
+	for name, path := range f.imports {
+		opts = append(opts, graphqlgenerator.WithImport(name, path))
+	}
+	for name, goType := range f.scalars {
+		opts = append(opts, graphqlgenerator.WithScalar(name, goType))
+	}
+	for name, goType := range f.modelTypes {
+		opts = append(opts, graphqlgenerator.WithModelType(name, goType))
+	}
+	return opts, nil
+}
+
+// mapFlag collects repeated name=value flags such as -scalar and -import.
+type mapFlag map[string]string
+
+func (s *mapFlag) String() string { return "" }
+
+func (s *mapFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected name=value, found %q", value)
+	}
+	if *s == nil {
+		*s = make(mapFlag)
+	}
+	(*s)[value[:i]] = value[i+1:]
+	return nil
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: graphqlgenerator %s [flags] schema.graphql...\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses the flags of a command and returns its schema paths.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		// The flag package has already reported the problem.
+		return nil, usageError{}
+	}
+	if fs.NArg() == 0 {
+		return nil, usageError{fmt.Sprintf("graphqlgenerator %s: no schema files given", fs.Name())}
+	}
+	return fs.Args(), nil
+}
+
+func runGenerate(args []string, stdout io.Writer, stderr io.Writer) error {
+	var gf generatorFlags
+	var output string
+	var check, watch bool
+	var interval time.Duration
+	fs := newFlagSet("generate", stderr)
+	gf.register(fs)
+	fs.StringVar(&output, "o", "", "output file; the configured output or standard output if empty")
+	fs.BoolVar(&check, "check", false, "report a diff and fail if the output file is out of date, without writing it")
+	fs.BoolVar(&watch, "watch", false, "keep regenerating the output file whenever the schema files change")
+	fs.DurationVar(&interval, "interval", 500*time.Millisecond, "how often -watch polls the schema files")
+	paths, configOutput, opts, err := gf.setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = configOutput
+	}
+	if check {
+		if output == "" {
+			return usageError{"graphqlgenerator generate: -check needs an output file"}
+		}
+		diff, err := graphqlgenerator.NewGenerator(opts...).CheckFiles(output, paths...)
+		if err != nil {
+			return err
+		}
+		if diff != "" {
+			fmt.Fprint(stdout, diff)
+			return fmt.Errorf("%s is out of date; run graphqlgenerator generate", output)
+		}
+		return nil
+	}
+	g := graphqlgenerator.NewGenerator(opts...)
+	if watch {
+		if output == "" {
+			return usageError{"graphqlgenerator generate: -watch needs an output file"}
+		}
+		ctx, stop := watchContext()
+		defer stop()
+		graphqlgenerator.Watch(ctx, paths, interval, interval/2, func() {
+			// Keep watching through errors; the schema is often invalid
+			// while it is being edited.
+			if err := writeOutput(g, output, paths, stdout); err != nil {
+				fmt.Fprintln(stderr, err)
+			}
+		})
+		return nil
+	}
+	if output == "" {
+		return g.GenerateFiles(stdout, paths...)
+	}
+	return writeOutput(g, output, paths, nil)
+}
+
+// watchContext returns the context ending generate -watch, which is done on
+// an interrupt.
+var watchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// writeOutput generates from paths into output, leaving the file untouched
+// if its content is unchanged. Files written are reported to log, if given.
+func writeOutput(g *graphqlgenerator.Generator, output string, paths []string, log io.Writer) error {
+	var buf bytes.Buffer
+	if err := g.GenerateFiles(&buf, paths...); err != nil {
+		return err
+	}
+	if current, err := ioutil.ReadFile(output); err == nil && bytes.Equal(current, buf.Bytes()) {
+		return nil
+	}
+	if err := ioutil.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if log != nil {
+		fmt.Fprintf(log, "wrote %s\n", output)
+	}
+	return nil
+}
+
+func runValidate(args []string, stdout io.Writer, stderr io.Writer) error {
+	var gf generatorFlags
+	fs := newFlagSet("validate", stderr)
+	gf.register(fs)
+	paths, _, opts, err := gf.setup(fs, args)
+	if err != nil {
+		return err
+	}
+	return graphqlgenerator.NewGenerator(opts...).GenerateFiles(ioutil.Discard, paths...)
+}
+
+func runScaffold(args []string, stdout io.Writer, stderr io.Writer) error {
+	var gf generatorFlags
+	var output string
+	fs := newFlagSet("scaffold", stderr)
+	gf.register(fs)
+	fs.StringVar(&output, "o", "", "resolver file to add stubs to; created if missing")
+	paths, _, opts, err := gf.setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return usageError{"graphqlgenerator scaffold: -o is required"}
+	}
+	src, mismatches, err := graphqlgenerator.NewGenerator(opts...).ScaffoldFiles(output, paths...)
+	if err != nil {
+		return err
+	}
+	if current, err := ioutil.ReadFile(output); err != nil || !bytes.Equal(current, src) {
+		if err := ioutil.WriteFile(output, src, 0644); err != nil {
+			return err
+		}
+	}
+	for _, mismatch := range mismatches {
+		fmt.Fprintln(stderr, mismatch)
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d resolver methods do not match the schema", len(mismatches))
+	}
+	return nil
+}
+
+func runFormat(args []string, stdout io.Writer, stderr io.Writer) error {
+	var list, diff bool
+	fs := newFlagSet("format", stderr)
+	fs.BoolVar(&list, "l", false, "list files whose formatting differs, without rewriting them")
+	fs.BoolVar(&diff, "d", false, "print diffs to the canonical layout, without rewriting the files")
+	paths, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	files, err := schemaFiles(paths)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := graphqlgenerator.Format(path, data)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(formatted, data) {
+			continue
+		}
+		if list {
+			fmt.Fprintln(stdout, path)
+		}
+		if diff {
+			d, err := graphqlgenerator.FormatDiff(path, data)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(stdout, d)
+		}
+		if list || diff {
+			continue
+		}
+		if err := ioutil.WriteFile(path, formatted, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaFiles returns paths with each directory replaced by the .graphql
+// files under it.
+func schemaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && filepath.Ext(name) == ".graphql" {
+				files = append(files, name)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func runPrint(args []string, stdout io.Writer, stderr io.Writer) error {
+	paths, err := parseArgs(newFlagSet("print", stderr), args)
+	if err != nil {
+		return err
+	}
+	doc := &graphqlgenerator.Document{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		fileDoc, err := graphqlgenerator.ParseSchema(path, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err := doc.Merge(fileDoc); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return graphqlgenerator.Fprint(stdout, doc)
+}
+
+func runIntrospect(args []string, stdout io.Writer, stderr io.Writer) error {
+	var gf generatorFlags
+	var output string
+	fs := newFlagSet("introspect", stderr)
+	gf.register(fs)
+	fs.StringVar(&output, "o", "", "output file; standard output if empty")
+	paths, _, opts, err := gf.setup(fs, args)
+	if err != nil {
+		return err
+	}
+	g := graphqlgenerator.NewGenerator(opts...)
+	if output == "" {
+		return g.IntrospectFiles(stdout, paths...)
+	}
+	var buf bytes.Buffer
+	if err := g.IntrospectFiles(&buf, paths...); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(output, buf.Bytes(), 0644)
+}
+
+// compareReport is the JSON report of compare.
+type compareReport struct {
+	Breaking  int                       `json:"breaking"`
+	Dangerous int                       `json:"dangerous"`
+	Safe      int                       `json:"safe"`
+	Changes   []graphqlgenerator.Change `json:"changes"`
+}
+
+func runCompare(args []string, stdout io.Writer, stderr io.Writer) error {
+	var jsonReport bool
+	fs := newFlagSet("compare", stderr)
+	fs.BoolVar(&jsonReport, "json", false, "write the changes as a JSON report")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: graphqlgenerator compare [flags] old.graphql new.graphql")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return usageError{}
+	}
+	if fs.NArg() != 2 {
+		return usageError{"graphqlgenerator compare: expected an old and a new schema"}
+	}
+	before, err := schemaFiles(fs.Args()[:1])
+	if err != nil {
+		return err
+	}
+	after, err := schemaFiles(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	changes, err := graphqlgenerator.CompareFiles(before, after)
+	if err != nil {
+		return err
+	}
+	report := compareReport{Changes: changes}
+	for _, change := range changes {
+		switch change.Severity {
+		case graphqlgenerator.Breaking:
+			report.Breaking++
+		case graphqlgenerator.Dangerous:
+			report.Dangerous++
+		default:
+			report.Safe++
+		}
+	}
+	if jsonReport {
+		if report.Changes == nil {
+			report.Changes = []graphqlgenerator.Change{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s\n", data)
+	} else {
+		for _, change := range changes {
+			fmt.Fprintln(stdout, change)
+		}
+	}
+	if report.Breaking > 0 {
+		return fmt.Errorf("%d breaking changes", report.Breaking)
+	}
+	return nil
+}
+
+func runReverse(args []string, stdout io.Writer, stderr io.Writer) error {
+	var packageName string
+	fs := newFlagSet("reverse", stderr)
+	fs.StringVar(&packageName, "package", "", "package clause of the printed schema")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: graphqlgenerator reverse [flags] types.go...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return usageError{}
+	}
+	if fs.NArg() == 0 {
+		return usageError{"graphqlgenerator reverse: no Go files given"}
+	}
+	doc, err := graphqlgenerator.ParseGoFiles(fs.Args()...)
+	if err != nil {
+		return err
+	}
+	doc.Package = packageName
+	return graphqlgenerator.Fprint(stdout, doc)
+}
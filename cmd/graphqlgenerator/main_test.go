@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeSchema(t *testing.T, dir string, name string, schema string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_Generate(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "type Query {\n  timeseries: int\n}\n")
+	output := filepath.Join(dir, "schema_gen.go")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"generate", "-package", "models", "-o", output, schema}, &stdout, &stderr); code != 0 {
+		t.Errorf("generate exited with %d: %s", code, stderr.String())
+	}
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "var Query = graphql.NewObject") {
+		t.Errorf("generate wrote %s", data)
+	}
+}
+
+func Test_GenerateDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries int\n}\n")
+	output := filepath.Join(dir, "schema_gen.go")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"generate", "-o", output, schema}, &stdout, &stderr); code != 1 {
+		t.Errorf("generate exited with %d instead of 1", code)
+	}
+	if want := schema + ":3:14: "; !strings.HasPrefix(stderr.String(), want) {
+		t.Errorf("generate reported %q, expected it to start with %q", stderr.String(), want)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("generate wrote %s despite the error", output)
+	}
+	if code := run([]string{"generate", "-style", "bad", schema}, &stdout, &stderr); code != 2 {
+		t.Errorf("generate exited with %d instead of 2 for a bad flag", code)
+	}
+}
+
+func Test_Format(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries:int\n   name : String!}\n")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"format", schema}, &stdout, &stderr); code != 0 {
+		t.Errorf("format exited with %d: %s", code, stderr.String())
+	}
+	data, err := ioutil.ReadFile(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package models\n\ntype Query {\n  timeseries: Int\n  name: String!\n}\n"
+	if string(data) != want {
+		t.Errorf("format wrote\n%s\ninstead of\n%s", data, want)
+	}
+}
+
+func Test_FormatList(t *testing.T) {
+	dir := t.TempDir()
+	formatted := writeSchema(t, dir, "a.graphql", "# Users.\ntype User {\n  name: String # full name\n}\n")
+	messy := writeSchema(t, dir, "b.graphql", "type Query { # root\n  user: User }\n")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"format", "-l", dir}, &stdout, &stderr); code != 0 {
+		t.Errorf("format -l exited with %d: %s", code, stderr.String())
+	}
+	if stdout.String() != messy+"\n" {
+		t.Errorf("format -l printed %q instead of %q", stdout.String(), messy+"\n")
+	}
+	stdout.Reset()
+	if code := run([]string{"format", "-d", formatted, messy}, &stdout, &stderr); code != 0 {
+		t.Errorf("format -d exited with %d: %s", code, stderr.String())
+	}
+	want := "--- " + messy + "\n+++ " + messy + " (formatted)\n@@ -1,2 +1,4 @@\n-type Query { # root\n-  user: User }\n+type Query {\n+  # root\n+  user: User\n+}\n"
+	if stdout.String() != want {
+		t.Errorf("format -d printed\n%s\ninstead of\n%s", stdout.String(), want)
+	}
+	data, err := ioutil.ReadFile(messy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "type Query { # root\n  user: User }\n" {
+		t.Errorf("format -l or -d rewrote %s:\n%s", messy, data)
+	}
+}
+
+func Test_GenerateConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "schema.graphql", "type Query {\n  timeseries: int\n}\n")
+	config := writeSchema(t, dir, "graphqlgenerator.json", `{"inputs": ["schema.graphql"], "output": "schema_gen.go", "package": "models"}`)
+	var stdout, stderr strings.Builder
+	if code := run([]string{"generate", "-config", config, "-package", "api"}, &stdout, &stderr); code != 0 {
+		t.Errorf("generate exited with %d: %s", code, stderr.String())
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "schema_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "package api") {
+		t.Errorf("generate did not let -package override the configuration, wrote %s", data)
+	}
+
+	// The command line completes a configuration without inputs or mode.
+	config = writeSchema(t, dir, "graphqlgenerator.json", `{"package": "models", "resolvers": true, "suffix": "Type"}`)
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"generate", "-config", config, "-mode", "all", filepath.Join(dir, "schema.graphql")}, &stdout, &stderr); code != 0 {
+		t.Errorf("generate exited with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "type QueryResolver interface") {
+		t.Errorf("generate did not combine -mode all with the configured resolvers, returned %s", stdout.String())
+	}
+	stderr.Reset()
+	if code := run([]string{"generate", "-config", config}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "no inputs given") {
+		t.Errorf("generate exited with %d for a configuration without inputs: %s", code, stderr.String())
+	}
+}
+
+func Test_GenerateCheck(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries: int\n}\n")
+	output := filepath.Join(dir, "schema_gen.go")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"generate", "-o", output, schema}, &stdout, &stderr); code != 0 {
+		t.Fatalf("generate exited with %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"generate", "-check", "-o", output, schema}, &stdout, &stderr); code != 0 {
+		t.Errorf("generate -check exited with %d for an up to date file: %s", code, stderr.String())
+	}
+
+	writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  total: int\n}\n")
+	if code := run([]string{"generate", "-check", "-o", output, schema}, &stdout, &stderr); code != 1 {
+		t.Errorf("generate -check exited with %d instead of 1 for a stale file", code)
+	}
+	if !strings.Contains(stdout.String(), `+		"total": &graphql.Field{`) {
+		t.Errorf("generate -check printed %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "out of date") {
+		t.Errorf("generate -check reported %s", stderr.String())
+	}
+}
+
+func Test_GenerateWatch(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries int\n}\n")
+	output := filepath.Join(dir, "schema_gen.go")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func(f func() (context.Context, context.CancelFunc)) { watchContext = f }(watchContext)
+	watchContext = func() (context.Context, context.CancelFunc) { return ctx, cancel }
+	var stdout, stderr syncBuilder
+	done := make(chan int, 1)
+	go func() {
+		done <- run([]string{"generate", "-watch", "-interval", "2ms", "-o", output, schema}, &stdout, &stderr)
+	}()
+
+	waitFor(t, "the parse error", func() bool { return strings.Contains(stderr.String(), schema+":3:14: ") })
+	writeSchema(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries: int\n}\n")
+	waitFor(t, "the output", func() bool { return strings.Contains(stdout.String(), "wrote "+output) })
+	cancel()
+	if code := <-done; code != 0 {
+		t.Errorf("generate -watch exited with %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Error(err)
+	}
+}
+
+// waitFor polls cond until it holds, failing the test if it does not within
+// five seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// syncBuilder is a strings.Builder safe for concurrent use.
+type syncBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuilder) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuilder) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func Test_Scaffold(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "type Query {\n  count: Int!\n}\n")
+	output := filepath.Join(dir, "resolvers.go")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"scaffold", "-package", "models", "-o", output, schema}, &stdout, &stderr); code != 0 {
+		t.Errorf("scaffold exited with %d: %s", code, stderr.String())
+	}
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `func (queryResolver) Count(ctx context.Context) (int, error) {`) {
+		t.Errorf("scaffold wrote %s", data)
+	}
+
+	edited := strings.Replace(string(data), "(int, error)", "(int64, error)", 1)
+	writeSchema(t, dir, "resolvers.go", edited)
+	stderr.Reset()
+	if code := run([]string{"scaffold", "-package", "models", "-o", output, schema}, &stdout, &stderr); code != 1 {
+		t.Errorf("scaffold exited with %d instead of 1 for a mismatched method", code)
+	}
+	if want := output + ":18:22: method queryResolver.Count has signature (context.Context) (int64, error), expected (context.Context) (int, error)\n"; !strings.HasPrefix(stderr.String(), want) {
+		t.Errorf("scaffold reported %q, expected it to start with %q", stderr.String(), want)
+	}
+}
+
+func Test_Reverse(t *testing.T) {
+	dir := t.TempDir()
+	types := writeSchema(t, dir, "types.go", `package legacy
+
+import "github.com/graphql-go/graphql"
+
+var queryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:   "Query",
+	Fields: graphql.Fields{"count": &graphql.Field{Type: graphql.Int}},
+})
+`)
+	var stdout, stderr strings.Builder
+	if code := run([]string{"reverse", "-package", "legacy", types}, &stdout, &stderr); code != 0 {
+		t.Errorf("reverse exited with %d: %s", code, stderr.String())
+	}
+	if want := "package legacy\n\ntype Query {\n  count: Int\n}\n"; stdout.String() != want {
+		t.Errorf("reverse printed %q instead of %q", stdout.String(), want)
+	}
+}
+
+func Test_Introspect(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeSchema(t, dir, "schema.graphql", "type Query {\n  when: Time\n}\n")
+	output := filepath.Join(dir, "schema.json")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"introspect", "-scalar", "Time=graphql.DateTime", "-o", output, schema}, &stdout, &stderr); code != 0 {
+		t.Errorf("introspect exited with %d: %s", code, stderr.String())
+	}
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Schema struct {
+			QueryType struct{ Name string }
+			Types     []struct{ Kind, Name string }
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Schema.QueryType.Name != "Query" {
+		t.Errorf("introspect wrote query type %q", result.Schema.QueryType.Name)
+	}
+	var names []string
+	for _, typ := range result.Schema.Types[:7] {
+		names = append(names, typ.Kind+" "+typ.Name)
+	}
+	if want := "OBJECT Query, SCALAR Int, SCALAR Float, SCALAR String, SCALAR Boolean, SCALAR ID, SCALAR Time"; strings.Join(names, ", ") != want {
+		t.Errorf("introspect wrote types %s instead of %s", strings.Join(names, ", "), want)
+	}
+
+	// print reads introspection results back.
+	stdout.Reset()
+	if code := run([]string{"print", output}, &stdout, &stderr); code != 0 {
+		t.Errorf("print exited with %d: %s", code, stderr.String())
+	}
+	if want := "type Query {\n  when: Time\n}\n\nscalar Time\n"; stdout.String() != want {
+		t.Errorf("print printed %q instead of %q", stdout.String(), want)
+	}
+}
+
+func Test_Compare(t *testing.T) {
+	dir := t.TempDir()
+	old := writeSchema(t, dir, "old.graphql", "type Query {\n  user: String\n  count: Int\n}\n")
+	compatible := writeSchema(t, dir, "compatible.graphql", "type Query {\n  user: String!\n  count: Int\n  total: Int\n}\n")
+	breaking := writeSchema(t, dir, "breaking.graphql", "type Query {\n  user: String\n}\n")
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"compare", old, compatible}, &stdout, &stderr); code != 0 {
+		t.Errorf("compare exited with %d: %s", code, stderr.String())
+	}
+	if want := "safe: Query.user changed type from String to String!.\nsafe: Query.total was added.\n"; stdout.String() != want {
+		t.Errorf("compare printed %q instead of %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"compare", "-json", old, breaking}, &stdout, &stderr); code != 1 {
+		t.Errorf("compare exited with %d instead of 1 for a breaking change", code)
+	}
+	var report struct {
+		Breaking int
+		Changes  []struct{ Severity, Type, Path string }
+	}
+	if err := json.Unmarshal([]byte(stdout.String()), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Breaking != 1 || len(report.Changes) != 1 || report.Changes[0].Severity != "breaking" || report.Changes[0].Type != "FIELD_REMOVED" || report.Changes[0].Path != "Query.count" {
+		t.Errorf("compare reported %+v", report)
+	}
+	if !strings.Contains(stderr.String(), "1 breaking changes") {
+		t.Errorf("compare printed %q to standard error", stderr.String())
+	}
+	if code := run([]string{"compare", old}, &stdout, &stderr); code != 2 {
+		t.Errorf("compare exited with %d instead of 2 for one schema", code)
+	}
+}
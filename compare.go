@@ -0,0 +1,341 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity classifies a schema change by its effect on existing clients.
+type Severity int
+
+const (
+	// Safe changes keep every existing operation valid.
+	Safe Severity = iota
+	// Dangerous changes keep operations valid but may change what clients
+	// receive, such as a new enum value or default.
+	Dangerous
+	// Breaking changes make existing operations invalid or their results
+	// wrong.
+	Breaking
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Safe:
+		return "safe"
+	case Dangerous:
+		return "dangerous"
+	case Breaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Change is a difference between two versions of a schema. Type names the
+// kind of change as graphql-js does, such as FIELD_REMOVED, and Path the
+// element changed, such as User.name or User.friends(first).
+type Change struct {
+	Severity Severity `json:"severity"`
+	Type     string   `json:"type"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s", c.Severity, c.Message)
+}
+
+// CompareFiles reads the schema files at before and at after, each as one
+// schema, and returns the changes from the first to the second.
+func CompareFiles(before []string, after []string) ([]Change, error) {
+	old, _, err := readFiles(before)
+	if err != nil {
+		return nil, err
+	}
+	doc, _, err := readFiles(after)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(old, doc), nil
+}
+
+// Compare returns the changes from the schema before to the schema after,
+// classified by the rules of graphql-js: removing types, fields, arguments,
+// enum values, union members and interfaces, changing a type incompatibly
+// and adding required arguments or input fields break clients; adding enum
+// values, union members, interfaces and optional arguments or input fields
+// and changing defaults are dangerous; anything else is safe. Changes are
+// listed in the order of the types of before, followed by the types added.
+func Compare(before *Document, after *Document) []Change {
+	c := &comparer{}
+	for i := range before.Types {
+		old := &before.Types[i]
+		obj := after.Type(old.Name)
+		if obj == nil {
+			c.add(Breaking, "TYPE_REMOVED", old.Name, "%s was removed.", old.Name)
+			continue
+		}
+		c.compareType(old, obj)
+	}
+	for i := range after.Types {
+		if obj := &after.Types[i]; before.Type(obj.Name) == nil {
+			c.add(Safe, "TYPE_ADDED", obj.Name, "%s %s was added.", kindNoun(obj.Kind), obj.Name)
+		}
+	}
+	return c.changes
+}
+
+// comparer collects the changes between two schemas.
+type comparer struct {
+	changes []Change
+}
+
+func (c *comparer) add(severity Severity, typ string, path string, format string, a ...interface{}) {
+	c.changes = append(c.changes, Change{Severity: severity, Type: typ, Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// kindNoun returns the name of a kind of type for messages.
+func kindNoun(kind Kind) string {
+	switch kind {
+	case KindObject:
+		return "Object type"
+	case KindInterface:
+		return "Interface"
+	case KindInput:
+		return "Input type"
+	case KindEnum:
+		return "Enum"
+	case KindScalar:
+		return "Scalar"
+	case KindUnion:
+		return "Union"
+	}
+	return kind.String()
+}
+
+func (c *comparer) compareType(old *GqlModel, obj *GqlModel) {
+	if old.Kind != obj.Kind {
+		c.add(Breaking, "TYPE_CHANGED_KIND", obj.Name, "%s changed from %s to %s.", obj.Name, strings.ToLower(kindNoun(old.Kind)), strings.ToLower(kindNoun(obj.Kind)))
+		return
+	}
+	c.compareDescription(obj.Name, old.Description, obj.Description)
+	switch obj.Kind {
+	case KindObject, KindInterface:
+		c.compareNames(obj.Name, old.Interfaces, obj.Interfaces, "IMPLEMENTED_INTERFACE_REMOVED", "INTERFACE_ADDED_TO_OBJECT", "Interface")
+		c.compareFields(old, obj)
+	case KindInput:
+		c.compareInputFields(old, obj)
+	case KindEnum:
+		c.compareValues(old, obj)
+	case KindUnion:
+		c.compareNames(obj.Name, old.Types, obj.Types, "TYPE_REMOVED_FROM_UNION", "TYPE_ADDED_TO_UNION", "Member")
+	}
+}
+
+// compareNames compares the interfaces of an object or interface, or the
+// members of a union, which what names in messages.
+func (c *comparer) compareNames(path string, old []string, names []string, removed string, added string, what string) {
+	for _, name := range old {
+		if !containsName(names, name) {
+			c.add(Breaking, removed, path, "%s %s was removed from %s.", what, name, path)
+		}
+	}
+	for _, name := range names {
+		if !containsName(old, name) {
+			c.add(Dangerous, added, path, "%s %s was added to %s.", what, name, path)
+		}
+	}
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparer) compareDescription(path string, old string, description string) {
+	if old != description {
+		c.add(Safe, "DESCRIPTION_CHANGED", path, "Description of %s changed.", path)
+	}
+}
+
+func (c *comparer) compareDeprecation(path string, old []Directive, directives []Directive) {
+	was, is := hasDirective(old, "deprecated"), hasDirective(directives, "deprecated")
+	switch {
+	case is && !was:
+		c.add(Safe, "DEPRECATION_ADDED", path, "%s was deprecated.", path)
+	case was && !is:
+		c.add(Safe, "DEPRECATION_REMOVED", path, "%s is no longer deprecated.", path)
+	}
+}
+
+// hasDirective reports whether the named directive is applied.
+func hasDirective(directives []Directive, name string) bool {
+	for _, directive := range directives {
+		if directive.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compareFields compares the fields of an object or interface.
+func (c *comparer) compareFields(old *GqlModel, obj *GqlModel) {
+	for i := range old.Variables {
+		oldField := &old.Variables[i]
+		path := obj.Name + "." + oldField.Name
+		field := obj.Field(oldField.Name)
+		if field == nil {
+			c.add(Breaking, "FIELD_REMOVED", path, "%s was removed.", path)
+			continue
+		}
+		from, to := fieldType(oldField), fieldType(field)
+		if from.String() != to.String() {
+			severity := Breaking
+			if safeOutputChange(from, to) {
+				severity = Safe
+			}
+			c.add(severity, "FIELD_CHANGED_KIND", path, "%s changed type from %s to %s.", path, from, to)
+		}
+		c.compareDescription(path, oldField.Description, field.Description)
+		c.compareDeprecation(path, oldField.Directives, field.Directives)
+		c.compareArgs(path, oldField.Arg, field.Arg)
+	}
+	for _, field := range obj.Variables {
+		if old.Field(field.Name) == nil {
+			path := obj.Name + "." + field.Name
+			c.add(Safe, "FIELD_ADDED", path, "%s was added.", path)
+		}
+	}
+}
+
+func (c *comparer) compareArgs(field string, old []GqlArg, args []GqlArg) {
+	for i := range old {
+		oldArg := &old[i]
+		path := field + "(" + oldArg.Name + ")"
+		arg := findArg(args, oldArg.Name)
+		if arg == nil {
+			c.add(Breaking, "ARG_REMOVED", path, "Argument %s was removed from %s.", oldArg.Name, field)
+			continue
+		}
+		from, to := argType(oldArg), argType(arg)
+		if from.String() != to.String() {
+			severity := Breaking
+			if safeInputChange(from, to) {
+				severity = Safe
+			}
+			c.add(severity, "ARG_CHANGED_KIND", path, "Argument %s of %s changed type from %s to %s.", arg.Name, field, from, to)
+		}
+		if oldArg.Default != arg.Default {
+			c.add(Dangerous, "ARG_DEFAULT_VALUE_CHANGE", path, "Argument %s of %s has changed default value from %s to %s.", arg.Name, field, defaultString(oldArg.Default), defaultString(arg.Default))
+		}
+		c.compareDescription(path, oldArg.Description, arg.Description)
+	}
+	for i := range args {
+		arg := &args[i]
+		if findArg(old, arg.Name) != nil {
+			continue
+		}
+		path := field + "(" + arg.Name + ")"
+		if arg.Required && arg.Default == "" {
+			c.add(Breaking, "REQUIRED_ARG_ADDED", path, "Required argument %s was added to %s.", arg.Name, field)
+		} else {
+			c.add(Dangerous, "OPTIONAL_ARG_ADDED", path, "Optional argument %s was added to %s.", arg.Name, field)
+		}
+	}
+}
+
+func (c *comparer) compareInputFields(old *GqlModel, obj *GqlModel) {
+	for i := range old.Variables {
+		oldField := &old.Variables[i]
+		path := obj.Name + "." + oldField.Name
+		field := obj.Field(oldField.Name)
+		if field == nil {
+			c.add(Breaking, "FIELD_REMOVED", path, "%s was removed.", path)
+			continue
+		}
+		from, to := fieldType(oldField), fieldType(field)
+		if from.String() != to.String() {
+			severity := Breaking
+			if safeInputChange(from, to) {
+				severity = Safe
+			}
+			c.add(severity, "FIELD_CHANGED_KIND", path, "%s changed type from %s to %s.", path, from, to)
+		}
+		if oldField.Default != field.Default {
+			c.add(Dangerous, "FIELD_DEFAULT_VALUE_CHANGE", path, "%s has changed default value from %s to %s.", path, defaultString(oldField.Default), defaultString(field.Default))
+		}
+		c.compareDescription(path, oldField.Description, field.Description)
+	}
+	for _, field := range obj.Variables {
+		if old.Field(field.Name) != nil {
+			continue
+		}
+		path := obj.Name + "." + field.Name
+		if field.Required && field.Default == "" {
+			c.add(Breaking, "REQUIRED_INPUT_FIELD_ADDED", path, "Required input field %s was added.", path)
+		} else {
+			c.add(Dangerous, "OPTIONAL_INPUT_FIELD_ADDED", path, "Optional input field %s was added.", path)
+		}
+	}
+}
+
+func (c *comparer) compareValues(old *GqlModel, obj *GqlModel) {
+	find := func(values []EnumValue, name string) *EnumValue {
+		for i := range values {
+			if values[i].Name == name {
+				return &values[i]
+			}
+		}
+		return nil
+	}
+	for _, oldValue := range old.Values {
+		path := obj.Name + "." + oldValue.Name
+		value := find(obj.Values, oldValue.Name)
+		if value == nil {
+			c.add(Breaking, "VALUE_REMOVED_FROM_ENUM", path, "%s was removed from enum %s.", oldValue.Name, obj.Name)
+			continue
+		}
+		c.compareDescription(path, oldValue.Description, value.Description)
+		c.compareDeprecation(path, oldValue.Directives, value.Directives)
+	}
+	for _, value := range obj.Values {
+		if find(old.Values, value.Name) == nil {
+			c.add(Dangerous, "VALUE_ADDED_TO_ENUM", obj.Name+"."+value.Name, "%s was added to enum %s.", value.Name, obj.Name)
+		}
+	}
+}
+
+// defaultString returns a default value for messages.
+func defaultString(text string) string {
+	if text == "" {
+		return "none"
+	}
+	return text
+}
+
+// safeOutputChange reports whether a field whose type changes from old to typ
+// still returns what clients expect: the same named type, wrapped in the
+// same lists, and non-null wherever it was.
+func safeOutputChange(old gqlType, typ gqlType) bool {
+	return old.name() == typ.name() && old.list == typ.list &&
+		(!old.required || typ.required) &&
+		(!old.itemRequired || typ.itemRequired)
+}
+
+// safeInputChange reports whether an argument or input field whose type
+// changes from old to typ still accepts every value it did: the same named
+// type, wrapped in the same lists, and nullable wherever it was.
+func safeInputChange(old gqlType, typ gqlType) bool {
+	return old.name() == typ.name() && old.list == typ.list &&
+		(old.required || !typ.required) &&
+		(old.itemRequired || !typ.itemRequired)
+}
@@ -0,0 +1,80 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Compare(t *testing.T) {
+	before := `interface Node { key: ID! }
+type User implements Node {
+  key: ID!
+  name: String
+  email: String!
+  tags: [String!]
+  friends(first: Int = 10, after: String, order: Order): [User]
+}
+enum Order { ASC DESC }
+enum Role { ADMIN USER }
+input Filter { name: String, limit: Int! }
+union Result = User | Group
+type Group { id: ID }
+scalar Date
+`
+	after := `interface Node { key: ID! }
+"A user."
+type User {
+  key: ID!
+  name: String!
+  email: String
+  tags: [String]
+  friends(first: Int = 20, after: Int, order: Order!, "New." since: Date, level: Int!): [User] @deprecated
+}
+enum Order { ASC DESC RANDOM }
+enum Role { ADMIN }
+input Filter { name: String!, limit: Int, page: Int, size: Int! }
+union Result = User
+interface Group { id: ID }
+type Query { me: User }
+`
+	parse := func(src string) *Document {
+		doc, err := NewParser(strings.NewReader(src)).ParseDocument()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+	var got []string
+	for _, change := range Compare(parse(before), parse(after)) {
+		got = append(got, change.Severity.String()+" "+change.Type+" "+change.Path+": "+change.Message)
+	}
+	want := []string{
+		"safe DESCRIPTION_CHANGED User: Description of User changed.",
+		"breaking IMPLEMENTED_INTERFACE_REMOVED User: Interface Node was removed from User.",
+		"safe FIELD_CHANGED_KIND User.name: User.name changed type from String to String!.",
+		"breaking FIELD_CHANGED_KIND User.email: User.email changed type from String! to String.",
+		"breaking FIELD_CHANGED_KIND User.tags: User.tags changed type from [String!] to [String].",
+		"safe DEPRECATION_ADDED User.friends: User.friends was deprecated.",
+		"dangerous ARG_DEFAULT_VALUE_CHANGE User.friends(first): Argument first of User.friends has changed default value from 10 to 20.",
+		"breaking ARG_CHANGED_KIND User.friends(after): Argument after of User.friends changed type from String to Int.",
+		"breaking ARG_CHANGED_KIND User.friends(order): Argument order of User.friends changed type from Order to Order!.",
+		"dangerous OPTIONAL_ARG_ADDED User.friends(since): Optional argument since was added to User.friends.",
+		"breaking REQUIRED_ARG_ADDED User.friends(level): Required argument level was added to User.friends.",
+		"dangerous VALUE_ADDED_TO_ENUM Order.RANDOM: RANDOM was added to enum Order.",
+		"breaking VALUE_REMOVED_FROM_ENUM Role.USER: USER was removed from enum Role.",
+		"breaking FIELD_CHANGED_KIND Filter.name: Filter.name changed type from String to String!.",
+		"safe FIELD_CHANGED_KIND Filter.limit: Filter.limit changed type from Int! to Int.",
+		"dangerous OPTIONAL_INPUT_FIELD_ADDED Filter.page: Optional input field Filter.page was added.",
+		"breaking REQUIRED_INPUT_FIELD_ADDED Filter.size: Required input field Filter.size was added.",
+		"breaking TYPE_REMOVED_FROM_UNION Result: Member Group was removed from Result.",
+		"breaking TYPE_CHANGED_KIND Group: Group changed from object type to interface.",
+		"breaking TYPE_REMOVED Date: Date was removed.",
+		"safe TYPE_ADDED Query: Object type Query was added.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Compare returned\n%s\ninstead of\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if changes := Compare(parse(before), parse(before)); len(changes) != 0 {
+		t.Errorf("Compare returned %v for equal schemas", changes)
+	}
+}
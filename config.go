@@ -0,0 +1,236 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFile is the conventional name of the project configuration file.
+const ConfigFile = "graphqlgenerator.json"
+
+// Config holds the generator settings of a project, normally loaded from a
+// JSON file committed next to the schema:
+//
+//	{
+//		"inputs": ["schema/*.graphql"],
+//		"output": "models/schema_gen.go",
+//		"package": "models",
+//		"style": "thunk",
+//		"mode": "all",
+//		"header": "Schema types for the users service.",
+//		"scalars": {"DateTime": "graphql.DateTime", "Money": "money.Scalar"},
+//		"imports": {"money": "github.com/acme/money"},
+//		"modelTypes": {"DateTime": "time.Time"},
+//		"id": "graphql.ID",
+//		"directives": ["deprecated"],
+//		"templates": "templates/*.tmpl",
+//		"prefix": "",
+//		"suffix": "Type",
+//		"resolvers": true
+//	}
+//
+// Paths are relative to the directory of the configuration file. Inputs may
+// be glob patterns.
+type Config struct {
+	// Inputs are the schema files to generate from, unless the command
+	// line names them.
+	Inputs []string `json:"inputs"`
+	// Output is the generated Go file.
+	Output string `json:"output"`
+	// Package is the package name of the generated code.
+	Package string `json:"package"`
+	// Style is the output style, "var" or "thunk".
+	Style string `json:"style"`
+	// Mode selects the output, "schema", "models" or "all".
+	Mode string `json:"mode"`
+	// Header is comment text written at the top of the generated file.
+	Header string `json:"header"`
+	// Scalars maps GraphQL type names to Go expressions.
+	Scalars map[string]string `json:"scalars"`
+	// Imports maps package names used in Go expressions to import paths.
+	Imports map[string]string `json:"imports"`
+	// ModelTypes maps GraphQL scalars to the Go types of model fields.
+	ModelTypes map[string]string `json:"modelTypes"`
+	// ID is the Go expression used for the ID scalar.
+	ID string `json:"id"`
+	// Directives lists the directives to honor. Nil keeps the default.
+	Directives []string `json:"directives"`
+	// Templates is a glob of template files redefining the built in
+	// templates.
+	Templates string `json:"templates"`
+	// Prefix and Suffix wrap the Go names of the generated values.
+	Prefix string `json:"prefix"`
+	Suffix string `json:"suffix"`
+	// Resolvers wires the fields to generated resolver interfaces. It
+	// requires the "all" mode, here or on the command line.
+	Resolvers bool `json:"resolvers"`
+
+	dir string
+}
+
+// LoadConfig reads and validates the configuration file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{dir: filepath.Dir(path)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, configError(data, err))
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// configError rewrites a JSON decoding error in terms of the configuration
+// file, with a position where one is known.
+func configError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset counts the offending byte.
+		return fmt.Errorf("%s: %v", offsetPos(data, syntaxErr.Offset-1), syntaxErr)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%s: key %q must be %s", offsetPos(data, typeErr.Offset), typeErr.Field, typeErr.Type)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return fmt.Errorf("unknown key %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+// offsetPos returns the position of a byte offset in data.
+func offsetPos(data []byte, offset int64) Pos {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	return Pos{Line: line, Column: len(before) - bytes.LastIndexByte(before, '\n')}
+}
+
+// Validate reports settings that cannot be used. Settings that depend on
+// others, which the command line may supply, are left to the commands and
+// the generator: Paths requires inputs, and Generate requires the "all"
+// mode for resolvers.
+func (c *Config) Validate() error {
+	if c.Style != "" {
+		if _, err := ParseStyle(c.Style); err != nil {
+			return err
+		}
+	}
+	if c.Mode != "" {
+		if _, err := ParseMode(c.Mode); err != nil {
+			return err
+		}
+	}
+	for _, name := range c.Directives {
+		if !supportedDirectives[name] {
+			return fmt.Errorf("unsupported directive %q", name)
+		}
+	}
+	return nil
+}
+
+// Paths returns the schema files named by Inputs, expanding glob patterns.
+func (c *Config) Paths() ([]string, error) {
+	if len(c.Inputs) == 0 {
+		return nil, fmt.Errorf("no inputs given")
+	}
+	var paths []string
+	for _, input := range c.Inputs {
+		matches, err := filepath.Glob(c.path(input))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("input %s matches no files", input)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// OutputPath returns the path of the generated file, or "" if none is set.
+func (c *Config) OutputPath() string {
+	if c.Output == "" {
+		return ""
+	}
+	return c.path(c.Output)
+}
+
+// Options returns the generator options for the settings.
+func (c *Config) Options() ([]Option, error) {
+	var opts []Option
+	if c.Package != "" {
+		opts = append(opts, WithPackageName(c.Package))
+	}
+	if c.Style != "" {
+		style, err := ParseStyle(c.Style)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithStyle(style))
+	}
+	if c.Mode != "" {
+		mode, err := ParseMode(c.Mode)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMode(mode))
+	}
+	if c.Header != "" {
+		opts = append(opts, WithHeader(c.Header))
+	}
+	for name, path := range c.Imports {
+		opts = append(opts, WithImport(name, path))
+	}
+	for name, goType := range c.Scalars {
+		opts = append(opts, WithScalar(name, goType))
+	}
+	for name, goType := range c.ModelTypes {
+		opts = append(opts, WithModelType(name, goType))
+	}
+	if c.ID != "" {
+		opts = append(opts, WithScalar("ID", c.ID))
+	}
+	if c.Directives != nil {
+		opts = append(opts, WithDirectives(c.Directives...))
+	}
+	if c.Prefix != "" {
+		opts = append(opts, WithNamePrefix(c.Prefix))
+	}
+	if c.Suffix != "" {
+		opts = append(opts, WithNameSuffix(c.Suffix))
+	}
+	if c.Resolvers {
+		opts = append(opts, WithResolvers())
+	}
+	if c.Templates != "" {
+		t, err := DefaultTemplates().ParseGlob(c.path(c.Templates))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTemplates(t))
+	}
+	return opts, nil
+}
+
+// path resolves a path from the configuration file.
+func (c *Config) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(c.dir, name)
+}
@@ -0,0 +1,85 @@
+package graphqlgenerator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir string, name string, data string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "schema.graphql", "type User {\n  key: ID!\n  created: DateTime\n  name: String @deprecated\n}\n")
+	config := writeFile(t, dir, ConfigFile, `{
+	"inputs": ["*.graphql"],
+	"output": "schema_gen.go",
+	"package": "models",
+	"style": "thunk",
+	"scalars": {"DateTime": "graphql.DateTime"},
+	"id": "graphql.ID",
+	"directives": [],
+	"suffix": "Type"
+}`)
+	c, err := LoadConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.OutputPath() != filepath.Join(dir, "schema_gen.go") {
+		t.Errorf("OutputPath returned %s", c.OutputPath())
+	}
+	paths, err := c.Paths()
+	if err != nil {
+		t.Error(err)
+	}
+	opts, err := c.Options()
+	if err != nil {
+		t.Error(err)
+	}
+	var b strings.Builder
+	if err := NewGenerator(opts...).GenerateFiles(&b, paths...); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{"package models", "var UserType = ", "graphql.NewNonNull(graphql.ID)", "Type: graphql.DateTime", "graphql.FieldsThunk("} {
+		if !strings.Contains(out, want) {
+			t.Errorf("configured output is missing %q, returned %s", want, out)
+		}
+	}
+	if strings.Contains(out, "DeprecationReason") {
+		t.Errorf("configured output honors @deprecated although no directives are enabled, returned %s", out)
+	}
+}
+
+func Test_LoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		`{"inputs": ["a.graphql"], "pakage": "models"}`:      `unknown key "pakage"`,
+		`{"inputs": ["a.graphql"], "style": "inline"}`:       `unknown style "inline"`,
+		`{"inputs": ["a.graphql"], "directives": ["skip"]}`:  `unsupported directive "skip"`,
+		"{\n  \"inputs\": \"a.graphql\"\n}":                  `2:24: key "inputs" must be []string`,
+		"{\n  \"inputs\": [\"a.graphql\"],\n  \"output\": }": `3:13: `,
+	}
+	for data, want := range tests {
+		config := writeFile(t, dir, ConfigFile, data)
+		_, err := LoadConfig(config)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig(%s) returned %v, expected an error containing %q", data, err, want)
+		}
+	}
+
+	c, err := LoadConfig(writeFile(t, dir, ConfigFile, `{"package": "models", "resolvers": true}`))
+	if err != nil {
+		t.Fatalf("LoadConfig rejected settings the command line may complete: %v", err)
+	}
+	if _, err := c.Paths(); err == nil || err.Error() != "no inputs given" {
+		t.Errorf("Paths returned %v for a configuration without inputs", err)
+	}
+}
@@ -0,0 +1,282 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CheckFiles generates from the schema files at paths in memory and compares
+// the result with the file at output, which is left untouched. It returns a
+// unified diff from the file to the expected content, or "" if the file is
+// up to date. A missing file counts as empty.
+func (g *Generator) CheckFiles(output string, paths ...string) (string, error) {
+	var buf bytes.Buffer
+	if err := g.GenerateFiles(&buf, paths...); err != nil {
+		return "", err
+	}
+	current, err := ioutil.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return unifiedDiff(output, output+" (generated)", current, buf.Bytes()), nil
+}
+
+// FormatDiff returns a unified diff from src, the content of the schema
+// file name, to its canonical layout, or "" if it is already formatted.
+func FormatDiff(name string, src []byte) (string, error) {
+	formatted, err := Format(name, src)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(name, name+" (formatted)", src, formatted), nil
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffMaxCost bounds the steps of the search for a middle snake, after
+// which the diff settles for an edit script that may not be the shortest,
+// so that unrelated large files are compared in near linear time.
+const diffMaxCost = 1024
+
+// diffLine is one line of an edit script: ' ' for a line kept, '-' for a
+// line removed from the old text and '+' for a line added by the new one.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns the unified diff between old and new, or "" if they
+// are equal.
+func unifiedDiff(oldName string, newName string, old []byte, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+	edits := diffLines(splitLines(old), splitLines(new))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+		// Start a hunk with up to diffContext lines before the change and
+		// extend it while changes are closer than twice that.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end += diffContext
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		for _, e := range edits[start:end] {
+			b.WriteByte(e.op)
+			b.WriteString(e.text)
+			if !strings.HasSuffix(e.text, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				oldLine++
+			}
+			if e.op != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats the start and length of a hunk.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		// An empty range names the line before it.
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits data after each newline.
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n') + 1
+		if i == 0 {
+			i = len(data)
+		}
+		lines = append(lines, string(data[:i]))
+		data = data[i:]
+	}
+	return lines
+}
+
+// diffLines returns a shortest edit script turning a into b, found with
+// the linear space variant of Myers' O(ND) algorithm.
+func diffLines(a []string, b []string) []diffLine {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	// List the lines removed by each change before those it adds.
+	edits := d.edits
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].op != ' ' {
+			j++
+		}
+		sort.SliceStable(edits[i:j], func(x, y int) bool {
+			return edits[i+x].op == '-' && edits[i+y].op == '+'
+		})
+		i = j
+	}
+	return edits
+}
+
+// differ accumulates the edit script between a and b.
+type differ struct {
+	a, b  []string
+	edits []diffLine
+}
+
+// compare appends the edits turning a[aLo:aHi] into b[bLo:bHi], splitting
+// them at a middle snake until one side is empty.
+func (d *differ) compare(aLo int, aHi int, bLo int, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, diffLine{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-1-suffix] == d.b[bHi-1-suffix] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.edits = append(d.edits, diffLine{'+', line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.edits = append(d.edits, diffLine{'-', line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for _, line := range d.a[x:u] {
+			d.edits = append(d.edits, diffLine{' ', line})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for _, line := range d.a[aHi : aHi+suffix] {
+		d.edits = append(d.edits, diffLine{' ', line})
+	}
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the run of equal
+// lines in the middle of a shortest edit script turning a[aLo:aHi] into
+// b[bLo:bHi], searching forward from the start and backward from the end
+// until the paths meet. Diagonal k holds the points with x-y = k, relative
+// to aLo and bLo; forward[k] is the furthest x reached on it from the start
+// and backward[k-delta] the least x reached from the end.
+func (d *differ) middleSnake(aLo int, aHi int, bLo int, bHi int) (int, int, int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	offset := max + 1
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+	forward[offset+1] = 0
+	backward[offset-1] = n
+	for step := 0; step <= max; step++ {
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if c := k - delta; odd && c >= -(step-1) && c <= step-1 && x >= backward[offset+c] {
+				return aLo + startX, bLo + startY, aLo + x, bLo + y
+			}
+		}
+		for c := -step; c <= step; c += 2 {
+			var x int
+			if c == step || (c != -step && backward[offset+c+1] > backward[offset+c-1]) {
+				x = backward[offset+c-1]
+			} else {
+				x = backward[offset+c+1] - 1
+			}
+			y := x - (c + delta)
+			endX, endY := x, y
+			for x > 0 && y > 0 && d.a[aLo+x-1] == d.b[bLo+y-1] {
+				x--
+				y--
+			}
+			backward[offset+c] = x
+			if k := c + delta; !odd && k >= -step && k <= step && x <= forward[offset+k] {
+				return aLo + x, bLo + y, aLo + endX, bLo + endY
+			}
+		}
+		if step >= diffMaxCost {
+			// Split at the forward point furthest from the start instead.
+			bestX, bestY := -1, -1
+			for k := -step; k <= step; k += 2 {
+				x := forward[offset+k]
+				if y := x - k; x <= n && y <= m && x+y > bestX+bestY {
+					bestX, bestY = x, y
+				}
+			}
+			if bestX >= 0 {
+				return aLo + bestX, bLo + bestY, aLo + bestX, bLo + bestY
+			}
+		}
+	}
+	panic("diff: no middle snake")
+}
@@ -0,0 +1,93 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_UnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+	if got := unifiedDiff("old", "new", []byte(old), []byte(new)); got != want {
+		t.Errorf("unifiedDiff returned\n%s\ninstead of\n%s", got, want)
+	}
+	if got := unifiedDiff("old", "new", []byte(old), []byte(old)); got != "" {
+		t.Errorf("unifiedDiff returned %q for equal inputs", got)
+	}
+}
+
+func Test_DiffLinesLarge(t *testing.T) {
+	// A table of the common subsequences of these would take 20GB.
+	var a, b []string
+	for i := 0; i < 50000; i++ {
+		a = append(a, fmt.Sprintf("a%d\n", i))
+		if i%10 == 0 {
+			b = append(b, a[i])
+		} else {
+			b = append(b, fmt.Sprintf("b%d\n", i))
+		}
+	}
+	var gotA, gotB []string
+	for _, e := range diffLines(a, b) {
+		if e.op != '+' {
+			gotA = append(gotA, e.text)
+		}
+		if e.op != '-' {
+			gotB = append(gotB, e.text)
+		}
+	}
+	if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+		t.Errorf("diffLines returned an edit script that does not turn a into b")
+	}
+}
+
+func Test_CheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeFile(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries: int\n}\n")
+	output := filepath.Join(dir, "schema_gen.go")
+	g := NewGenerator()
+
+	diff, err := g.CheckFiles(output, schema)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(diff, "+var Query = graphql.NewObject(") {
+		t.Errorf("CheckFiles did not report the missing output, returned %s", diff)
+	}
+
+	var b strings.Builder
+	if err := g.GenerateFiles(&b, schema); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "schema_gen.go", b.String())
+	if diff, err = g.CheckFiles(output, schema); err != nil || diff != "" {
+		t.Errorf("CheckFiles reported an up to date file as stale: %v %s", err, diff)
+	}
+
+	writeFile(t, dir, "schema.graphql", "package models\ntype Query {\n  timeseries: int\n  total: int\n}\n")
+	if diff, err = g.CheckFiles(output, schema); err != nil || !strings.Contains(diff, `+		"total": &graphql.Field{`) {
+		t.Errorf("CheckFiles did not report the new field, returned %v %s", err, diff)
+	}
+	data, err := ioutil.ReadFile(output)
+	if err != nil || string(data) != b.String() {
+		t.Errorf("CheckFiles modified the output file")
+	}
+}
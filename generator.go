@@ -1,7 +1,12 @@
 package graphqlgenerator
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
 	"io/ioutil"
 	"log"
@@ -9,132 +14,744 @@ import (
 	"strings"
 )
 
-func tokenType(token Token) string {
+// Version is the generator version recorded in generated files.
+const Version = "v0.1.0"
+
+// scalarName returns the GraphQL name of a built in scalar token, or "" if
+// the token does not name one.
+func scalarName(token Token) string {
 	switch token {
 	case STRING:
-		return "graphql.String"
+		return "String"
 	case FLOAT:
-		return "graphql.Float"
+		return "Float"
 	case INT:
-		return "graphql.Int"
+		return "Int"
 	case BOOLEAN:
-		return "graphql.Boolean"
+		return "Boolean"
 	case ID:
-		return "graphql.String"
-	case IDENT:
-		return "lit"
+		return "ID"
 	}
 	return ""
 }
 
-func gqlObjString(elements string, name string) string {
-	return fmt.Sprintf(`var %s = graphql.NewObject(graphql.ObjectConfig{
-    Name: "%s",
-    Fields: graphql.Fields{ 
-        %s
-    },
-})
-`, name, name, elements)
+// typeExpr returns the Go expression referring to the GraphQL type named by
+// tok and lit, applying the generator's scalar mapping.
+func (g *Generator) typeExpr(tok Token, lit string) (ast.Expr, error) {
+	name := scalarName(tok)
+	if name == "" {
+		name = lit
+	}
+	goType, ok := g.scalars[name]
+	if !ok {
+		return ast.NewIdent(g.goName(name)), nil
+	}
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Go type %q for %s: %v", goType, name, err)
+	}
+	return expr, nil
+}
+
+// wrapType applies the list and non-null modifiers to a type expression.
+func wrapType(typ ast.Expr, list bool, itemRequired bool, required bool) ast.Expr {
+	if list {
+		if itemRequired {
+			typ = gqlCall("NewNonNull", typ)
+		}
+		typ = gqlCall("NewList", typ)
+	}
+	if required {
+		typ = gqlCall("NewNonNull", typ)
+	}
+	return typ
+}
 
+// typeDecl returns the declaration of the graphql-go value for obj, or nil
+// for scalars, which are referenced through the scalar mapping or a Go value
+// of the same name.
+func (g *Generator) typeDecl(obj GqlModel) (ast.Decl, error) {
+	switch obj.Kind {
+	case KindObject:
+		return g.objectDecl(obj)
+	case KindInterface:
+		return g.interfaceDecl(obj)
+	case KindInput:
+		return g.inputDecl(obj)
+	case KindEnum:
+		return g.enumDecl(obj)
+	case KindUnion:
+		return g.unionDecl(obj)
+	}
+	return nil, nil
 }
 
-func gqlListString(name string, typename string, arg string, argCheck bool, required bool) string {
-	if required == true {
-		typename = fmt.Sprintf(`graphql.NewNonNull(%s)`, typename)
+// initDecl returns an init function adding the fields of a cyclic type and
+// the ResolveType of an interface or union, or nil if obj needs neither. Go
+// rejects package variables whose initializers refer back to themselves,
+// even through a thunk, so these are left out of the declarations.
+func (g *Generator) initDecl(obj GqlModel) (ast.Decl, error) {
+	body := &ast.BlockStmt{}
+	if g.cyclic[obj.Name] {
+		var fields *ast.CompositeLit
+		var err error
+		switch obj.Kind {
+		case KindObject, KindInterface:
+			fields, err = g.fieldsExpr(obj)
+		case KindInput:
+			fields, err = g.inputFieldsExpr(obj)
+		default:
+			fields = &ast.CompositeLit{}
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, elt := range fields.Elts {
+			field := elt.(*ast.KeyValueExpr)
+			call := &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: ast.NewIdent(g.goName(obj.Name)), Sel: ast.NewIdent("AddFieldConfig")},
+				Args: []ast.Expr{field.Key, field.Value},
+			}
+			body.List = append(body.List, &ast.ExprStmt{X: call})
+		}
 	}
-	if argCheck == true {
-		return fmt.Sprintf(`"%s": &graphql.Field{
-            Type: graphql.NewList(%s),
-            Args: graphql.FieldConfigArgument{
-            	%s
-            },
-        },
-        `, name, typename, arg)
+	if resolve := g.resolveTypeExpr(obj); resolve != nil {
+		body.List = append(body.List, &ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.SelectorExpr{X: ast.NewIdent(g.goName(obj.Name)), Sel: ast.NewIdent("ResolveType")}},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{resolve},
+		})
 	}
-	return fmt.Sprintf(`"%s": &graphql.Field{
-            Type: graphql.NewList(%s),
-        },
-        `, name, typename)
+	if len(body.List) == 0 {
+		return nil, nil
+	}
+	return &ast.FuncDecl{
+		Name: ast.NewIdent("init"),
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+		Body: body,
+	}, nil
 }
 
-func gqlElementString(name string, typename string, arg string, argCheck bool, required bool) string {
-	if required == true {
-		typename = fmt.Sprintf(`graphql.NewNonNull(%s)`, typename)
+// resolveTypeExpr returns the function giving the object type of a value of
+// the interface or union obj from the type of its model, or nil unless the
+// models are generated with the types.
+func (g *Generator) resolveTypeExpr(obj GqlModel) ast.Expr {
+	var members []string
+	switch {
+	case g.mode != ModeAll:
+		return nil
+	case obj.Kind == KindInterface:
+		members = g.implementations(obj.Name)
+	case obj.Kind == KindUnion:
+		members = obj.Types
+	default:
+		return nil
+	}
+	cases := &ast.BlockStmt{}
+	for _, name := range members {
+		cases.List = append(cases.List, &ast.CaseClause{
+			List: []ast.Expr{&ast.StarExpr{X: ast.NewIdent(modelName(name))}, ast.NewIdent(modelName(name))},
+			Body: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent(g.goName(name))}}},
+		})
 	}
-	if argCheck == true {
-		return fmt.Sprintf(`"%s": &graphql.Field{
-            Type: %s,
-            Args: graphql.FieldConfigArgument{
-            	%s
-            },
-        },
-        `, name, typename, arg)
+	value := &ast.SelectorExpr{X: ast.NewIdent("p"), Sel: ast.NewIdent("Value")}
+	return &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{{
+				Names: []*ast.Ident{ast.NewIdent("p")},
+				Type:  gqlSel("ResolveTypeParams"),
+			}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.StarExpr{X: gqlSel("Object")}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.TypeSwitchStmt{Assign: &ast.ExprStmt{X: &ast.TypeAssertExpr{X: value}}, Body: cases},
+			&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}},
+		}},
 	}
-	return fmt.Sprintf(`"%s": &graphql.Field{
-            Type: %s,
-        },
-        `, name, typename)
 }
 
-func gqlArgString(name string, argType string, required bool, argDefault string) string {
-	if required == true {
-		argType = fmt.Sprintf(`graphql.NewNonNull(%s)`, argType)
+// cyclicTypes returns the names of the types of doc whose declarations
+// would refer back to themselves, directly or through other types.
+func (g *Generator) cyclicTypes(doc *Document) map[string]bool {
+	refs := make(map[string][]string)
+	for _, obj := range doc.Types {
+		var names []string
+		for i := range obj.Variables {
+			field := &obj.Variables[i]
+			names = append(names, fieldType(field).name())
+			for j := range field.Arg {
+				names = append(names, argType(&field.Arg[j]).name())
+			}
+		}
+		refs[obj.Name] = append(append(names, obj.Interfaces...), obj.Types...)
+	}
+	cyclic := make(map[string]bool)
+	for name := range refs {
+		seen := make(map[string]bool)
+		var reaches func(from string) bool
+		reaches = func(from string) bool {
+			for _, to := range refs[from] {
+				if to == name {
+					return true
+				}
+				if !seen[to] {
+					seen[to] = true
+					if reaches(to) {
+						return true
+					}
+				}
+			}
+			return false
+		}
+		if reaches(name) {
+			cyclic[name] = true
+		}
 	}
-	if argDefault != "" {
-		argDefault = fmt.Sprintf(`DefaultValue: %s,`, argDefault)
+	return cyclic
+}
+
+// thunk wraps body in a call of the graphql thunk type name, deferring its
+// evaluation until the schema is built.
+func thunk(name string, result ast.Expr, body ast.Expr) ast.Expr {
+	return gqlCall(name, &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: result}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{body}},
+		}},
+	})
+}
+
+// typeList returns a slice literal of the generated values for names.
+func (g *Generator) typeList(elt string, names []string) ast.Expr {
+	list := &ast.CompositeLit{Type: &ast.ArrayType{Elt: &ast.StarExpr{X: gqlSel(elt)}}}
+	for _, name := range names {
+		list.Elts = append(list.Elts, ast.NewIdent(g.goName(name)))
 	}
-	return fmt.Sprintf(`"%s": &graphql.ArgumentConfig{
-                Type: %s,
-                %s
-        		},`, name, argType, argDefault)
+	return list
+}
 
+// fieldsConfig returns the Fields entry of the config of an object or
+// interface, which is empty for a cyclic type: initDecl adds its fields.
+func (g *Generator) fieldsConfig(obj GqlModel) (ast.Expr, error) {
+	if g.cyclic[obj.Name] {
+		return &ast.CompositeLit{Type: gqlSel("Fields")}, nil
+	}
+	fields, err := g.fieldsExpr(obj)
+	if err != nil {
+		return nil, err
+	}
+	if g.style == StyleThunk {
+		return thunk("FieldsThunk", gqlSel("Fields"), fields), nil
+	}
+	return fields, nil
 }
 
-func GenerateToString(input io.Reader) (string, error) {
-	p := NewParser(input)
-	packageName, err := p.ParsePackage()
+func (g *Generator) objectDecl(obj GqlModel) (ast.Decl, error) {
+	fields, err := g.fieldsConfig(obj)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	config := &ast.CompositeLit{
+		Type: gqlSel("ObjectConfig"),
+		Elts: []ast.Expr{keyValue("Name", stringLit(obj.Name))},
+	}
+	if len(obj.Interfaces) > 0 {
+		config.Elts = append(config.Elts, keyValue("Interfaces", g.typeList("Interface", obj.Interfaces)))
+	}
+	config.Elts = append(config.Elts, keyValue("Fields", fields))
+	return varDecl(g.goName(obj.Name), gqlCall("NewObject", config)), nil
+}
 
-	obj, err := p.Parse()
+func (g *Generator) interfaceDecl(obj GqlModel) (ast.Decl, error) {
+	fields, err := g.fieldsConfig(obj)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	config := &ast.CompositeLit{
+		Type: gqlSel("InterfaceConfig"),
+		Elts: []ast.Expr{
+			keyValue("Name", stringLit(obj.Name)),
+			keyValue("Fields", fields),
+		},
 	}
-	toadd := fmt.Sprintf("package %s \n \n", packageName)
-	for obj != nil {
-		curadd := ""
-		for _, element := range obj.Variables {
+	return varDecl(g.goName(obj.Name), gqlCall("NewInterface", config)), nil
+}
 
-			tType := tokenType(element.Tok)
-			if tType == "lit" {
-				tType = element.Lit
+func (g *Generator) inputDecl(obj GqlModel) (ast.Decl, error) {
+	var fields ast.Expr = &ast.CompositeLit{Type: gqlSel("InputObjectConfigFieldMap")}
+	if !g.cyclic[obj.Name] {
+		fieldMap, err := g.inputFieldsExpr(obj)
+		if err != nil {
+			return nil, err
+		}
+		fields = fieldMap
+		if g.style == StyleThunk {
+			fields = thunk("InputObjectConfigFieldMapThunk", gqlSel("InputObjectConfigFieldMap"), fields)
+		}
+	}
+	config := &ast.CompositeLit{
+		Type: gqlSel("InputObjectConfig"),
+		Elts: []ast.Expr{
+			keyValue("Name", stringLit(obj.Name)),
+			keyValue("Fields", fields),
+		},
+	}
+	return varDecl(g.goName(obj.Name), gqlCall("NewInputObject", config)), nil
+}
+
+func (g *Generator) inputFieldsExpr(obj GqlModel) (*ast.CompositeLit, error) {
+	fieldMap := &ast.CompositeLit{Type: gqlSel("InputObjectConfigFieldMap")}
+	for _, element := range obj.Variables {
+		typ, err := g.typeExpr(element.Tok, element.Lit)
+		if err != nil {
+			return nil, err
+		}
+		field := &ast.CompositeLit{
+			Type: gqlSel("InputObjectFieldConfig"),
+			Elts: []ast.Expr{keyValue("Type", wrapType(typ, element.List, element.ItemRequired, element.Required))},
+		}
+		if element.Default != "" {
+			value, err := g.defaultExpr(fieldType(&element), element.Default)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", obj.Name, element.Name, err)
 			}
-			argString := ""
-			argCheck := false
+			field.Elts = append(field.Elts, keyValue("DefaultValue", value))
+		}
+		fieldMap.Elts = append(fieldMap.Elts, &ast.KeyValueExpr{Key: stringLit(element.Name), Value: addr(field)})
+	}
+	return fieldMap, nil
+}
+
+func (g *Generator) enumDecl(obj GqlModel) (ast.Decl, error) {
+	values := &ast.CompositeLit{Type: gqlSel("EnumValueConfigMap")}
+	for _, value := range obj.Values {
+		config := &ast.CompositeLit{
+			Type: gqlSel("EnumValueConfig"),
+			Elts: []ast.Expr{keyValue("Value", g.enumValueExpr(obj.Name, value.Name))},
+		}
+		reason, deprecated, err := g.deprecationReason(value.Name, value.Directives)
+		if err != nil {
+			return nil, err
+		}
+		if deprecated {
+			config.Elts = append(config.Elts, keyValue("DeprecationReason", stringLit(reason)))
+		}
+		values.Elts = append(values.Elts, &ast.KeyValueExpr{Key: stringLit(value.Name), Value: addr(config)})
+	}
+	config := &ast.CompositeLit{
+		Type: gqlSel("EnumConfig"),
+		Elts: []ast.Expr{
+			keyValue("Name", stringLit(obj.Name)),
+			keyValue("Values", values),
+		},
+	}
+	return varDecl(g.goName(obj.Name), gqlCall("NewEnum", config)), nil
+}
+
+// enumValueExpr returns the Go value graphql-go gives for the value of the
+// named enum: the constant of its model when models are generated with the
+// types, so that the values of model fields serialize, or else its name.
+func (g *Generator) enumValueExpr(enum string, value string) ast.Expr {
+	if g.mode == ModeAll {
+		return ast.NewIdent(enumConst(enum, value))
+	}
+	return stringLit(value)
+}
+
+func (g *Generator) unionDecl(obj GqlModel) (ast.Decl, error) {
+	config := &ast.CompositeLit{
+		Type: gqlSel("UnionConfig"),
+		Elts: []ast.Expr{
+			keyValue("Name", stringLit(obj.Name)),
+			keyValue("Types", g.typeList("Object", obj.Types)),
+		},
+	}
+	return varDecl(g.goName(obj.Name), gqlCall("NewUnion", config)), nil
+}
+
+func (g *Generator) fieldsExpr(obj GqlModel) (*ast.CompositeLit, error) {
+	fields := &ast.CompositeLit{Type: gqlSel("Fields")}
+	for _, element := range obj.Variables {
+		typ, err := g.typeExpr(element.Tok, element.Lit)
+		if err != nil {
+			return nil, err
+		}
+		field := &ast.CompositeLit{
+			Type: gqlSel("Field"),
+			Elts: []ast.Expr{keyValue("Type", wrapType(typ, element.List, element.ItemRequired, element.Required))},
+		}
+		if len(element.Arg) > 0 {
+			args := &ast.CompositeLit{Type: gqlSel("FieldConfigArgument")}
 			for _, arg := range element.Arg {
-				argType := tokenType(arg.Tok)
-				if argType == "lit" {
-					argType = arg.Lit
+				argExpr, err := g.argExpr(arg)
+				if err != nil {
+					return nil, err
+				}
+				args.Elts = append(args.Elts, &ast.KeyValueExpr{Key: stringLit(arg.Name), Value: argExpr})
+			}
+			field.Elts = append(field.Elts, keyValue("Args", args))
+		}
+		if g.hasResolver(&obj, &element) {
+			field.Elts = append(field.Elts, keyValue("Resolve", ast.NewIdent(resolveFunc(&obj, &element))))
+		}
+		reason, deprecated, err := g.deprecationReason(element.Name, element.Directives)
+		if err != nil {
+			return nil, err
+		}
+		if deprecated {
+			field.Elts = append(field.Elts, keyValue("DeprecationReason", stringLit(reason)))
+		}
+		fields.Elts = append(fields.Elts, &ast.KeyValueExpr{Key: stringLit(element.Name), Value: addr(field)})
+	}
+	return fields, nil
+}
+
+// deprecationReason returns the reason given by a @deprecated directive on
+// the named field or enum value, if the generator honors it.
+func (g *Generator) deprecationReason(name string, directives []Directive) (string, bool, error) {
+	if !g.directives["deprecated"] {
+		return "", false, nil
+	}
+	for _, directive := range directives {
+		if directive.Name != "deprecated" {
+			continue
+		}
+		value, ok := directive.Arg("reason")
+		if !ok {
+			return "No longer supported", true, nil
+		}
+		reason, err := unquote(value)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid @deprecated reason %s on %s", value, name)
+		}
+		return reason, true, nil
+	}
+	return "", false, nil
+}
+
+func (g *Generator) argExpr(arg GqlArg) (ast.Expr, error) {
+	typ, err := g.typeExpr(arg.Tok, arg.Lit)
+	if err != nil {
+		return nil, err
+	}
+	config := &ast.CompositeLit{
+		Type: gqlSel("ArgumentConfig"),
+		Elts: []ast.Expr{keyValue("Type", wrapType(typ, arg.List, arg.ItemRequired, arg.Required))},
+	}
+	if arg.Default != "" {
+		value, err := g.defaultExpr(argType(&arg), arg.Default)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", arg.Name, err)
+		}
+		config.Elts = append(config.Elts, keyValue("DefaultValue", value))
+	}
+	return addr(config), nil
+}
+
+// defaultExpr returns the default value of an argument or input field of
+// type typ, written in schema notation, as a Go expression of the value
+// graphql-go would coerce it to.
+func (g *Generator) defaultExpr(typ gqlType, text string) (ast.Expr, error) {
+	v, err := parseDefault(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default value %s: %v", text, err)
+	}
+	return g.valueExpr(typ, v)
+}
+
+// valueExpr returns the Go expression for v coerced to typ: lists become
+// []interface{}, input objects map[string]interface{} and enum values
+// the values of their enum.
+func (g *Generator) valueExpr(typ gqlType, v *value) (ast.Expr, error) {
+	if v.kind == nullValue {
+		return ast.NewIdent("nil"), nil
+	}
+	if typ.list {
+		item := gqlType{tok: typ.tok, lit: typ.lit, required: typ.itemRequired}
+		list := &ast.CompositeLit{Type: &ast.ArrayType{Elt: emptyInterface()}}
+		items := v.list
+		if v.kind != listValue {
+			items = []*value{v}
+		}
+		for _, element := range items {
+			expr, err := g.valueExpr(item, element)
+			if err != nil {
+				return nil, err
+			}
+			list.Elts = append(list.Elts, expr)
+		}
+		return list, nil
+	}
+
+	switch typ.tok {
+	case FLOAT:
+		if v.kind == intValue {
+			return &ast.BasicLit{Kind: token.FLOAT, Value: v.lit + ".0"}, nil
+		}
+	case ID:
+		if v.kind == intValue {
+			return stringLit(v.lit), nil
+		}
+	case IDENT:
+		def := g.doc.Type(typ.lit)
+		if def != nil && def.Kind == KindEnum && v.kind == enumValue {
+			return g.enumValueExpr(def.Name, v.lit), nil
+		}
+		if def == nil || def.Kind != KindInput || v.kind != objectValue {
+			break
+		}
+		fields := &ast.CompositeLit{Type: &ast.MapType{Key: ast.NewIdent("string"), Value: emptyInterface()}}
+		for _, field := range def.Variables {
+			given := v.field(field.Name)
+			if given == nil {
+				if field.Default == "" {
+					continue
 				}
-				argString += gqlArgString(arg.Name, argType, arg.Required, arg.Default)
-				argCheck = true
+				// Input coercion fills in the defaults of omitted fields.
+				var err error
+				if given, err = parseDefault(field.Default); err != nil {
+					return nil, fmt.Errorf("invalid default value %s: %v", field.Default, err)
+				}
+			}
+			expr, err := g.valueExpr(fieldType(&field), given)
+			if err != nil {
+				return nil, err
 			}
+			fields.Elts = append(fields.Elts, &ast.KeyValueExpr{Key: stringLit(field.Name), Value: expr})
+		}
+		return fields, nil
+	}
+	return literalExpr(v)
+}
 
-			if element.List == true {
-				curadd += gqlListString(element.Name, tType, argString, argCheck, element.Required)
-			} else {
-				curadd += gqlElementString(element.Name, tType, argString, argCheck, element.Required)
+// literalExpr returns the Go expression for v as written, for values of
+// scalar types the generator knows nothing about.
+func literalExpr(v *value) (ast.Expr, error) {
+	switch v.kind {
+	case intValue:
+		return &ast.BasicLit{Kind: token.INT, Value: v.lit}, nil
+	case floatValue:
+		return &ast.BasicLit{Kind: token.FLOAT, Value: v.lit}, nil
+	case stringValue:
+		s, err := unquote(v.lit)
+		if err != nil {
+			return nil, err
+		}
+		return stringLit(s), nil
+	case enumValue:
+		return stringLit(v.lit), nil
+	case listValue:
+		list := &ast.CompositeLit{Type: &ast.ArrayType{Elt: emptyInterface()}}
+		for _, element := range v.list {
+			expr, err := literalExpr(element)
+			if err != nil {
+				return nil, err
+			}
+			list.Elts = append(list.Elts, expr)
+		}
+		return list, nil
+	case objectValue:
+		fields := &ast.CompositeLit{Type: &ast.MapType{Key: ast.NewIdent("string"), Value: emptyInterface()}}
+		for _, field := range v.fields {
+			expr, err := literalExpr(field.value)
+			if err != nil {
+				return nil, err
 			}
+			fields.Elts = append(fields.Elts, &ast.KeyValueExpr{Key: stringLit(field.name), Value: expr})
 		}
-		toadd += gqlObjString(curadd, obj.Name)
-		obj, err = p.Parse()
+		return fields, nil
+	}
+	// true, false and null.
+	if v.kind == nullValue {
+		return ast.NewIdent("nil"), nil
+	}
+	return ast.NewIdent(v.lit), nil
+}
+
+// provenance returns the header marking the output as generated, recording
+// the sources it was generated from, the SHA-256 sum of the schema and the
+// generator version.
+func (g *Generator) provenance(sum []byte) string {
+	text := fmt.Sprintf("Code generated by graphqlgenerator %s. DO NOT EDIT.\n", Version)
+	if len(g.sources) > 0 {
+		text += fmt.Sprintf("source: %s\n", strings.Join(g.sources, ", "))
+	}
+	text += fmt.Sprintf("sha256: %x\n", sum)
+	return text
+}
+
+// Generate reads a schema, as text or an introspection result, from r and
+// writes the generated graphql-go types to w, configured by opts.
+func Generate(w io.Writer, r io.Reader, opts ...Option) error {
+	return NewGenerator(opts...).Generate(w, r)
+}
+
+// Generate reads a schema, as text or an introspection result, from r and
+// writes the generated graphql-go types to w.
+func (g *Generator) Generate(w io.Writer, r io.Reader) error {
+	hash := sha256.New()
+	doc, err := ParseSchema("", io.TeeReader(r, hash))
+	if err != nil {
+		return err
+	}
+	return g.generate(w, doc, hash.Sum(nil))
+}
+
+// GenerateFiles reads the schema files at paths, which may also hold
+// introspection results, as one schema and writes the generated graphql-go
+// types to w. Unless WithSources was given, the paths
+// are recorded as the sources of the output.
+func (g *Generator) GenerateFiles(w io.Writer, paths ...string) error {
+	doc, sum, err := readFiles(paths)
+	if err != nil {
+		return err
+	}
+	if len(g.sources) == 0 {
+		gen := *g
+		gen.sources = paths
+		g = &gen
+	}
+	return g.generate(w, doc, sum)
+}
+
+// readFiles parses the schema files at paths as one document and returns it
+// with the SHA-256 sum of their contents.
+func readFiles(paths []string) (*Document, []byte, error) {
+	hash := sha256.New()
+	doc := &Document{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
 		if err != nil {
-			fmt.Println(err)
+			return nil, nil, err
+		}
+		hash.Write(data)
+		fileDoc, err := ParseSchema(path, bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := doc.Merge(fileDoc); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
 		}
 	}
-	return toadd, nil
+	return doc, hash.Sum(nil), nil
+}
 
+// prepare checks the settings and doc and returns a copy of the generator
+// set up to generate doc, with the package name of the output.
+func (g *Generator) prepare(doc *Document) (*Generator, string, error) {
+	for name := range g.directives {
+		if !supportedDirectives[name] {
+			return nil, "", fmt.Errorf("unsupported directive @%s", name)
+		}
+	}
+	if g.resolvers && g.mode != ModeAll {
+		return nil, "", fmt.Errorf("resolvers return the models, so they require mode all")
+	}
+	if err := doc.validate(g.scalars); err != nil {
+		return nil, "", err
+	}
+	gen := *g
+	if gen.mode == ModeAll && gen.namePrefix == "" && gen.nameSuffix == "" {
+		// The models take the exported GraphQL names.
+		gen.nameSuffix = "Type"
+	}
+	names, err := gen.goNames(doc)
+	if err != nil {
+		return nil, "", err
+	}
+	gen.doc, gen.names = doc, names
+	gen.cyclic = gen.cyclicTypes(doc)
+	gen.embedded = gen.embeddedTypes(doc)
+	packageName := doc.Package
+	if g.packageName != "" {
+		packageName = g.packageName
+	}
+	if packageName == "" {
+		return nil, "", fmt.Errorf("missing package name")
+	}
+	return &gen, packageName, nil
+}
+
+// generate writes the graphql-go types for doc to w. sum is the SHA-256 sum
+// of the schema source recorded in the header.
+func (g *Generator) generate(w io.Writer, doc *Document, sum []byte) error {
+	g, packageName, err := g.prepare(doc)
+	if err != nil {
+		return err
+	}
+
+	var header bytes.Buffer
+	writeHeader(&header, g.provenance(sum))
+	writeHeader(&header, g.header)
+
+	var models []ast.Decl
+	if g.mode != ModeSchema {
+		for _, obj := range doc.Types {
+			decls, err := g.modelDecls(obj)
+			if err != nil {
+				return err
+			}
+			models = append(models, decls...)
+		}
+	}
+
+	var src []byte
+	if g.templates != nil && g.mode != ModeModels {
+		src, err = g.generateTemplates(doc, packageName, header.Bytes(), models)
+	} else {
+		file := &ast.File{Name: ast.NewIdent(packageName)}
+		if g.mode != ModeModels {
+			for _, obj := range doc.Types {
+				decl, err := g.typeDecl(obj)
+				if err != nil {
+					return err
+				}
+				if decl != nil {
+					file.Decls = append(file.Decls, decl)
+				}
+				init, err := g.initDecl(obj)
+				if err != nil {
+					return err
+				}
+				if init != nil {
+					file.Decls = append(file.Decls, init)
+				}
+			}
+		}
+		file.Decls = append(file.Decls, models...)
+		src, err = printFile(header.Bytes(), file)
+	}
+	if err != nil {
+		return err
+	}
+	if g.resolvers {
+		resolvers, err := g.resolverSource()
+		if err != nil {
+			return err
+		}
+		if resolvers != nil {
+			src = append(append(src, '\n'), resolvers...)
+			if src, err = formatSource(src); err != nil {
+				return err
+			}
+		}
+	}
+	if src, err = addImports(src, g.imports); err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func GenerateToString(input io.Reader) (string, error) {
+	var b strings.Builder
+	if err := Generate(&b, input); err != nil {
+		return "", err
+	}
+	return b.String(), nil
 }
 
 func GenerateToFile(schemafile string, outputfile string) {
@@ -143,9 +760,12 @@ func GenerateToFile(schemafile string, outputfile string) {
 		log.Fatal(err)
 	}
 	g := strings.NewReader(string(data))
-	toadd, err := GenerateToString(g)
+	var b strings.Builder
+	err = Generate(&b, g, WithSources(schemafile))
+	toadd := b.String()
 	if err != nil {
 		fmt.Println(err)
+		return
 	}
 	file, err := os.Create(outputfile)
 	if err != nil {
@@ -0,0 +1,407 @@
+package graphqlgenerator
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_GenerateToString(t *testing.T) {
+	testString := `package models
+type Query {
+  timeseries: int
+}
+type Mutation {
+  performance(word: int): PerformanceSummary!
+}
+type PerformanceSummary {
+  total: int
+}`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(out, `var Query = graphql.NewObject`) {
+		t.Errorf("GenerateToString did not generate Query, returned %s", out)
+	}
+	if !strings.Contains(out, `var Mutation = graphql.NewObject`) {
+		t.Errorf("GenerateToString did not generate Mutation, returned %s", out)
+	}
+}
+
+func Test_GenerateToStringParseError(t *testing.T) {
+	testString := `package models
+type Query {
+  timeseries: int
+}
+type Mutation {
+  performance(word int): PerformanceSummary!
+}
+type Later {
+  name: String
+}`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err == nil {
+		t.Errorf("GenerateToString did not return an error for a malformed type, returned %s", out)
+	}
+	if out != "" {
+		t.Errorf("GenerateToString returned partial output alongside an error: %s", out)
+	}
+}
+
+func Test_GenerateOptions(t *testing.T) {
+	testString := `type User {
+  key: ID!
+  created: DateTime
+  friends: [Profile]
+}
+type Profile {
+  name: String
+}`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString),
+		WithPackageName("models"),
+		WithScalar("ID", "graphql.ID"),
+		WithScalar("DateTime", "graphql.DateTime"),
+		WithHeader("Schema types for the users service."),
+		WithStyle(StyleThunk))
+	if err != nil {
+		t.Error(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"// Schema types for the users service.\n",
+		"package models",
+		"graphql.NewNonNull(graphql.ID)",
+		"Type: graphql.DateTime",
+		"graphql.FieldsThunk(func() graphql.Fields {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Generate output is missing %q, returned %s", want, out)
+		}
+	}
+
+	if err = Generate(&b, strings.NewReader(testString)); err == nil {
+		t.Errorf("Generate did not report a missing package name")
+	}
+}
+
+func Test_GenerateFormatted(t *testing.T) {
+	testString := `package models
+type Query {
+  performance(word: int = 100!, fish: Animal): [PerformanceSummary]!
+}
+type PerformanceSummary {
+  total: int
+}
+scalar Animal`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err != nil {
+		t.Error(err)
+	}
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		t.Error(err)
+	}
+	if string(formatted) != out {
+		t.Errorf("GenerateToString output is not gofmt clean, returned %s", out)
+	}
+
+	var b strings.Builder
+	err = Generate(&b, strings.NewReader(testString), WithScalar("Animal", "scalars.Animal("))
+	if err == nil {
+		t.Errorf("Generate did not report invalid generated code")
+	} else if !strings.Contains(err.Error(), "scalars.Animal(") {
+		t.Errorf("Generate error does not quote the offending line: %v", err)
+	}
+}
+
+func Test_GenerateEscapesNames(t *testing.T) {
+	testString := `package models
+type Query {
+  say"hi(word: String = "a"): int
+}`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(out, `"say\"hi": &graphql.Field{`) {
+		t.Errorf("GenerateToString did not escape the field name, returned %s", out)
+	}
+}
+
+func Test_GenerateProvenance(t *testing.T) {
+	testString := "package models\ntype Query {\n  timeseries: int\n}\n"
+	var b strings.Builder
+	if err := Generate(&b, strings.NewReader(testString), WithSources("schema/query.graphql")); err != nil {
+		t.Error(err)
+	}
+	out := b.String()
+	generated := regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+	if !generated.MatchString(out) {
+		t.Errorf("Generate output is missing the generated code comment, returned %s", out)
+	}
+	sum := fmt.Sprintf("// sha256: %x\n", sha256.Sum256([]byte(testString)))
+	for _, want := range []string{Version, "// source: schema/query.graphql\n", sum} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Generate output is missing %q, returned %s", want, out)
+		}
+	}
+}
+
+func Test_GenerateDeprecated(t *testing.T) {
+	testString := `package models
+type Query {
+  timeseries: int @deprecated(reason: "Use \"series\".")
+  total: int @deprecated
+}`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{`DeprecationReason: "Use \"series\".",`, `DeprecationReason: "No longer supported",`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GenerateToString output is missing %q, returned %s", want, out)
+		}
+	}
+}
+
+func Test_GenerateDefaults(t *testing.T) {
+	testString := `package models
+type Query {
+  users(ratio: Float = 1, ids: [ID] = 5, filter: Filter = {role: ADMIN}): [String]
+}
+input Filter {
+  role: Role!
+  path: String = "a\/b"
+}
+enum Role { ADMIN USER }`
+	out, err := GenerateToString(strings.NewReader(testString))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`DefaultValue: 1.0,`,
+		`DefaultValue: []interface{}{"5"},`,
+		"DefaultValue: map[string]interface{}{\n\t\t\t\t\t\t\"role\": \"ADMIN\",\n\t\t\t\t\t\t\"path\": \"a/b\",\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GenerateToString output is missing %q, returned %s", want, out)
+		}
+	}
+}
+
+func Test_GenerateImports(t *testing.T) {
+	testString := `package models
+type Query {
+  created: DateTime
+  price: Money
+  timeout: Duration
+  due: Date
+  config: YAML
+}`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString),
+		WithScalar("DateTime", "github.com/acme/scalars.DateTime"),
+		WithScalar("Date", "github.com/acme/calendar/v3.Date"),
+		WithScalar("YAML", "gopkg.in/yaml.v3.Node"),
+		WithScalar("Money", "money.Scalar"),
+		WithImport("money", "github.com/acme/money/v2"),
+		WithScalar("Duration", "scalars.Duration(time.Second)"),
+		WithImport("time", "time"),
+		WithImport("unused", "github.com/acme/unused"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package models
+
+import (
+	"time"
+
+	calendar "github.com/acme/calendar/v3"
+	money "github.com/acme/money/v2"
+	"github.com/acme/scalars"
+	"github.com/graphql-go/graphql"
+	yaml "gopkg.in/yaml.v3"
+)
+`
+	if out := b.String(); !strings.Contains(out, want) {
+		t.Errorf("Generate output is missing the imports\n%s\nreturned %s", want, out)
+	}
+	for _, want := range []string{"Type: calendar.Date,", "Type: yaml.Node,"} {
+		if out := b.String(); !strings.Contains(out, want) {
+			t.Errorf("Generate output is missing %q, returned %s", want, out)
+		}
+	}
+
+	b.Reset()
+	if err := Generate(&b, strings.NewReader("package models\nscalar Date\n")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "import") {
+		t.Errorf("Generate imported packages the output does not use, returned %s", b.String())
+	}
+}
+
+// runGenerated builds the generated source, as package models together
+// with any extra files of that package, with the main package mainSrc
+// against graphql-go in a temporary module and returns the output of
+// running it. It skips the test if graphql-go can not be downloaded.
+func runGenerated(t *testing.T, generated string, mainSrc string, extra ...string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/generated\n\ngo 1.18\n\nrequire github.com/graphql-go/graphql v0.8.1\n",
+		"main.go":          mainSrc,
+		"models/models.go": generated,
+	}
+	for i, src := range extra {
+		files[fmt.Sprintf("models/extra%d.go", i)] = src
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goCmd := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=mod", "GOSUMDB=off", "GOWORK=off")
+		return cmd.CombinedOutput()
+	}
+	if out, err := goCmd("mod", "download", "github.com/graphql-go/graphql"); err != nil {
+		t.Skipf("graphql-go is not available: %v\n%s", err, out)
+	}
+	out, err := goCmd("run", ".")
+	if err != nil {
+		t.Fatalf("generated code failed: %v\n%s\n%s", err, out, generated)
+	}
+	return string(out)
+}
+
+func Test_GenerateCompiles(t *testing.T) {
+	testString := `package models
+type Query {
+  me: User
+  users(filter: Filter): [User!]
+}
+type User {
+  name: String
+  friends: [User!]
+  manager: User!
+  posts: [Post]
+}
+type Post {
+  author: User!
+}
+input Filter {
+  name: String
+  or: [Filter!]
+}`
+	const mainSrc = `package main
+
+import (
+	"fmt"
+
+	"example.com/generated/models"
+	"github.com/graphql-go/graphql"
+)
+
+func main() {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: models.%s})
+	if err != nil {
+		panic(err)
+	}
+	user := schema.Type("User").(*graphql.Object)
+	filter := schema.Type("Filter").(*graphql.InputObject)
+	fmt.Println(user.Fields()["friends"].Type, user.Fields()["posts"].Type, filter.Fields()["or"].Type)
+}
+`
+	for _, test := range []struct {
+		query string
+		opts  []Option
+	}{
+		{"Query", []Option{WithStyle(StyleVar)}},
+		{"Query", []Option{WithStyle(StyleThunk)}},
+		{"QueryType", []Option{WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers()}},
+	} {
+		var b strings.Builder
+		if err := Generate(&b, strings.NewReader(testString), test.opts...); err != nil {
+			t.Error(err)
+			continue
+		}
+		out := runGenerated(t, b.String(), fmt.Sprintf(mainSrc, test.query))
+		if want := "[User!] [Post] [Filter!]\n"; out != want {
+			t.Errorf("generated schema has field types %q, expected %q", out, want)
+		}
+	}
+}
+
+func Test_GenerateResolvesModels(t *testing.T) {
+	testString := `package models
+type Query {
+  node: Node
+  result: Result
+}
+interface Node { id: ID! }
+type User implements Node {
+  id: ID!
+  role: Role
+}
+enum Role { ADMIN SUPER_USER }
+union Result = User`
+	const mainSrc = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"example.com/generated/models"
+	"github.com/graphql-go/graphql"
+)
+
+func main() {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: models.QueryType})
+	if err != nil {
+		panic(err)
+	}
+	role := models.RoleSuperUser
+	user := &models.User{ID: "1", Role: &role}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: "{ node { __typename id } result { ... on User { role } } }",
+		RootObject:    map[string]interface{}{"node": user, "result": user},
+	})
+	out, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+}
+`
+	var b strings.Builder
+	if err := Generate(&b, strings.NewReader(testString), WithMode(ModeAll)); err != nil {
+		t.Fatal(err)
+	}
+	out := runGenerated(t, b.String(), mainSrc)
+	if want := `{"data":{"node":{"__typename":"User","id":"1"},"result":{"role":"SUPER_USER"}}}` + "\n"; out != want {
+		t.Errorf("generated schema returned %s, expected %s", out, want)
+	}
+}
@@ -0,0 +1,361 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// gqlSel returns the selector graphql.name.
+func gqlSel(name string) ast.Expr {
+	return &ast.SelectorExpr{X: ast.NewIdent("graphql"), Sel: ast.NewIdent(name)}
+}
+
+// gqlCall returns a call of the graphql package function name.
+func gqlCall(name string, args ...ast.Expr) ast.Expr {
+	return &ast.CallExpr{Fun: gqlSel(name), Args: args}
+}
+
+func stringLit(s string) ast.Expr {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+func keyValue(key string, value ast.Expr) ast.Expr {
+	return &ast.KeyValueExpr{Key: ast.NewIdent(key), Value: value}
+}
+
+// emptyInterface returns the type interface{}.
+func emptyInterface() ast.Expr {
+	return &ast.InterfaceType{Methods: &ast.FieldList{}}
+}
+
+func addr(x ast.Expr) ast.Expr {
+	return &ast.UnaryExpr{Op: token.AND, X: x}
+}
+
+func varDecl(name string, value ast.Expr) ast.Decl {
+	return &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{&ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(name)},
+			Values: []ast.Expr{value},
+		}},
+	}
+}
+
+// formatSource gofmts generated code. Errors here mean the generator emitted
+// invalid Go, so they quote the offending line of the generated code.
+func formatSource(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err == nil {
+		return formatted, nil
+	}
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		lines := strings.Split(string(src), "\n")
+		if line := list[0].Pos.Line; line > 0 && line <= len(lines) {
+			return nil, fmt.Errorf("generated invalid code: %v\n\t%s", list[0], strings.TrimSpace(lines[line-1]))
+		}
+	}
+	return nil, fmt.Errorf("generated invalid code: %v", err)
+}
+
+// addImports declares the packages of imports that src refers to and does
+// not import yet, standard library packages first, and returns the formatted
+// source.
+func addImports(src []byte, imports map[string]string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			imported[spec.Name.Name] = true
+		} else {
+			imported[path.Base(importPath)] = true
+		}
+	}
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && imports[x.Name] != "" && !imported[x.Name] {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+	if len(used) == 0 {
+		return src, nil
+	}
+
+	var std, other []string
+	for name := range used {
+		if strings.Contains(strings.SplitN(imports[name], "/", 2)[0], ".") {
+			other = append(other, name)
+		} else {
+			std = append(std, name)
+		}
+	}
+	byPath := func(names []string) {
+		sort.Slice(names, func(i, j int) bool { return imports[names[i]] < imports[names[j]] })
+	}
+	byPath(std)
+	byPath(other)
+	spec := func(name string) string {
+		if path.Base(imports[name]) == name {
+			return strconv.Quote(imports[name])
+		}
+		return name + " " + strconv.Quote(imports[name])
+	}
+
+	// Files that already import packages get the new ones in their import
+	// block, where gofmt sorts them.
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || !gen.Lparen.IsValid() {
+			continue
+		}
+		offset := fset.Position(gen.Rparen).Offset
+		var buf bytes.Buffer
+		buf.Write(src[:offset])
+		for _, name := range append(std, other...) {
+			buf.WriteString("\t" + spec(name) + "\n")
+		}
+		buf.Write(src[offset:])
+		return formatSource(buf.Bytes())
+	}
+
+	var decl bytes.Buffer
+	if len(used) == 1 {
+		for name := range used {
+			decl.WriteString("import " + spec(name) + "\n")
+		}
+	} else {
+		decl.WriteString("import (\n")
+		for _, name := range std {
+			decl.WriteString("\t" + spec(name) + "\n")
+		}
+		if len(std) > 0 && len(other) > 0 {
+			decl.WriteString("\n")
+		}
+		for _, name := range other {
+			decl.WriteString("\t" + spec(name) + "\n")
+		}
+		decl.WriteString(")\n")
+	}
+
+	offset := fset.Position(file.Name.End()).Offset
+	var buf bytes.Buffer
+	buf.Write(src[:offset])
+	buf.WriteString("\n\n")
+	buf.Write(decl.Bytes())
+	buf.Write(src[offset:])
+	return formatSource(buf.Bytes())
+}
+
+// printFile prints the generated syntax tree below the header comments and
+// returns the formatted source.
+func printFile(header []byte, file *ast.File) ([]byte, error) {
+	fset := token.NewFileSet()
+	layoutNode(fset, file)
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	if err := printer.Fprint(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return formatSource(buf.Bytes())
+}
+
+// writeHeader writes header as a comment followed by a blank line.
+func writeHeader(buf *bytes.Buffer, header string) {
+	if header == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(header, "\n"), "\n") {
+		buf.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+	buf.WriteString("\n")
+}
+
+// layout hands out line positions to a syntax tree built in memory. The
+// printer only breaks composite literals, blocks and declaration lists over
+// several lines when their positions say so.
+type layout struct {
+	file  *token.File
+	line  int
+	stack []ast.Node
+}
+
+// layoutNode positions every declaration of a file on its own lines and every
+// keyed composite literal element, statement and struct field within node on
+// a line of its own.
+func layoutNode(fset *token.FileSet, node ast.Node) {
+	nodes := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		nodes++
+		return true
+	})
+	lines := make([]int, 3*nodes+8)
+	for i := range lines {
+		lines[i] = i
+	}
+	l := &layout{file: fset.AddFile("", -1, len(lines))}
+	l.file.SetLines(lines)
+	l.line = 1
+	ast.Inspect(node, l.visit)
+}
+
+func (l *layout) pos() token.Pos { return l.file.LineStart(l.line) }
+
+func (l *layout) newline() token.Pos {
+	l.line++
+	return l.pos()
+}
+
+func (l *layout) visit(n ast.Node) bool {
+	if n == nil {
+		l.leave(l.stack[len(l.stack)-1])
+		l.stack = l.stack[:len(l.stack)-1]
+		return true
+	}
+	if len(l.stack) > 0 && l.startsLine(l.stack[len(l.stack)-1], n) {
+		l.newline()
+	}
+	l.stack = append(l.stack, n)
+
+	switch n := n.(type) {
+	case *ast.File:
+		n.Package = l.pos()
+		n.Name.NamePos = l.pos()
+	case *ast.GenDecl:
+		n.TokPos = l.pos()
+		if n.Lparen.IsValid() || len(n.Specs) > 1 {
+			n.Lparen = l.pos()
+		}
+	case *ast.FuncDecl:
+		n.Type.Func = l.pos()
+	case *ast.Ident:
+		n.NamePos = l.pos()
+	case *ast.BasicLit:
+		n.ValuePos = l.pos()
+	case *ast.CompositeLit:
+		n.Lbrace = l.pos()
+	case *ast.UnaryExpr:
+		n.OpPos = l.pos()
+	case *ast.StarExpr:
+		n.Star = l.pos()
+	case *ast.CallExpr:
+		n.Lparen = l.pos()
+	case *ast.FuncLit:
+		n.Type.Func = l.pos()
+	case *ast.BlockStmt:
+		n.Lbrace = l.pos()
+	case *ast.ReturnStmt:
+		n.Return = l.pos()
+	case *ast.TypeSwitchStmt:
+		n.Switch = l.pos()
+	case *ast.CaseClause:
+		n.Case = l.pos()
+	case *ast.FieldList:
+		n.Opening = l.pos()
+	}
+	return true
+}
+
+// startsLine reports whether child begins a new line within parent.
+func (l *layout) startsLine(parent ast.Node, child ast.Node) bool {
+	switch parent := parent.(type) {
+	case *ast.File:
+		if _, ok := child.(ast.Decl); ok {
+			// Leave a blank line before every declaration.
+			l.line++
+			return true
+		}
+	case *ast.GenDecl:
+		return parent.Lparen.IsValid()
+	case *ast.CompositeLit:
+		if multiline(parent) {
+			for _, elt := range parent.Elts {
+				if elt == child {
+					return true
+				}
+			}
+		}
+	case *ast.BlockStmt:
+		return true
+	case *ast.CaseClause:
+		_, ok := child.(ast.Stmt)
+		return ok
+	case *ast.FieldList:
+		return parent.Opening.IsValid() && l.inBlockType()
+	}
+	return false
+}
+
+func (l *layout) leave(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.GenDecl:
+		if n.Lparen.IsValid() {
+			n.Rparen = l.newline()
+		}
+	case *ast.CompositeLit:
+		if multiline(n) {
+			n.Rbrace = l.newline()
+		} else {
+			n.Rbrace = l.pos()
+		}
+	case *ast.CallExpr:
+		n.Rparen = l.pos()
+	case *ast.BlockStmt:
+		if len(n.List) == 0 {
+			n.Rbrace = l.pos()
+		} else {
+			n.Rbrace = l.newline()
+		}
+	case *ast.FieldList:
+		// Keep the braces of empty types such as interface{} together.
+		if len(n.List) > 0 && l.inBlockType() {
+			n.Closing = l.newline()
+		} else {
+			n.Closing = l.pos()
+		}
+	}
+}
+
+// multiline reports whether a composite literal lists one element per line.
+func multiline(lit *ast.CompositeLit) bool {
+	for _, elt := range lit.Elts {
+		if _, ok := elt.(*ast.KeyValueExpr); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inBlockType reports whether the field list on top of the stack belongs to
+// a struct or interface type.
+func (l *layout) inBlockType() bool {
+	return len(l.stack) > 1 && isBlockType(l.stack[len(l.stack)-2])
+}
+
+func isBlockType(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.StructType, *ast.InterfaceType:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,662 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Introspection is the result of the introspection query of the GraphQL
+// specification, as client code generators read it.
+type Introspection struct {
+	Schema IntrospectionSchema `json:"__schema"`
+}
+
+// IntrospectionSchema describes a schema: its root operation types, every
+// type and the directives it supports.
+type IntrospectionSchema struct {
+	QueryType        *IntrospectionTypeName   `json:"queryType"`
+	MutationType     *IntrospectionTypeName   `json:"mutationType"`
+	SubscriptionType *IntrospectionTypeName   `json:"subscriptionType"`
+	Types            []IntrospectionType      `json:"types"`
+	Directives       []IntrospectionDirective `json:"directives"`
+}
+
+// IntrospectionTypeName names a root operation type.
+type IntrospectionTypeName struct {
+	Name string `json:"name"`
+}
+
+// IntrospectionType describes a named type. Kind is one of SCALAR, OBJECT,
+// INTERFACE, UNION, ENUM and INPUT_OBJECT; the lists that do not apply to
+// the kind are null.
+type IntrospectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   *string                   `json:"description"`
+	Fields        []IntrospectionField      `json:"fields"`
+	InputFields   []IntrospectionInputValue `json:"inputFields"`
+	Interfaces    []IntrospectionTypeRef    `json:"interfaces"`
+	EnumValues    []IntrospectionEnumValue  `json:"enumValues"`
+	PossibleTypes []IntrospectionTypeRef    `json:"possibleTypes"`
+}
+
+// IntrospectionField describes a field of an object or interface.
+type IntrospectionField struct {
+	Name              string                    `json:"name"`
+	Description       *string                   `json:"description"`
+	Args              []IntrospectionInputValue `json:"args"`
+	Type              IntrospectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason *string                   `json:"deprecationReason"`
+}
+
+// IntrospectionInputValue describes an argument or input field. Its
+// default value is in schema notation.
+type IntrospectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  *string              `json:"description"`
+	Type         IntrospectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+// IntrospectionEnumValue describes a value of an enum.
+type IntrospectionEnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+// IntrospectionTypeRef refers to a type. LIST and NON_NULL refer to the type
+// they wrap with OfType; other kinds name a type.
+type IntrospectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   *string               `json:"name"`
+	OfType *IntrospectionTypeRef `json:"ofType"`
+}
+
+// IntrospectionDirective describes a directive and where it may be used.
+type IntrospectionDirective struct {
+	Name        string                    `json:"name"`
+	Description *string                   `json:"description"`
+	Locations   []string                  `json:"locations"`
+	Args        []IntrospectionInputValue `json:"args"`
+}
+
+// introspectionKinds maps the kinds of definitions to their introspection
+// names.
+var introspectionKinds = map[Kind]string{
+	KindObject:    "OBJECT",
+	KindInterface: "INTERFACE",
+	KindInput:     "INPUT_OBJECT",
+	KindEnum:      "ENUM",
+	KindScalar:    "SCALAR",
+	KindUnion:     "UNION",
+}
+
+// introspectionTypes defines the types of the introspection system, which
+// every schema includes.
+const introspectionTypes = `
+type __Schema {
+  types: [__Type!]!
+  queryType: __Type!
+  mutationType: __Type
+  subscriptionType: __Type
+  directives: [__Directive!]!
+}
+
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields: [__InputValue!]
+  ofType: __Type
+}
+
+enum __TypeKind {
+  SCALAR
+  OBJECT
+  INTERFACE
+  UNION
+  ENUM
+  INPUT_OBJECT
+  LIST
+  NON_NULL
+}
+
+type __Field {
+  name: String!
+  description: String
+  args: [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  defaultValue: String
+}
+
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __Directive {
+  name: String!
+  description: String
+  locations: [__DirectiveLocation!]!
+  args: [__InputValue!]!
+}
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+`
+
+// introspectionDirectives are the directives graphql-go supports.
+var introspectionDirectives = []struct {
+	name        string
+	description string
+	locations   []string
+	arg         GqlArg
+}{
+	{
+		name:        "include",
+		description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		arg:         GqlArg{Name: "if", Description: "Included when true.", Tok: BOOLEAN, Lit: "Boolean", Required: true},
+	},
+	{
+		name:        "skip",
+		description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		arg:         GqlArg{Name: "if", Description: "Skipped when true.", Tok: BOOLEAN, Lit: "Boolean", Required: true},
+	},
+	{
+		name:        "deprecated",
+		description: "Marks an element of a GraphQL schema as no longer supported.",
+		locations:   []string{"FIELD_DEFINITION", "ENUM_VALUE"},
+		arg:         GqlArg{Name: "reason", Description: "Explains why this element was deprecated.", Tok: STRING, Lit: "String", Default: `"No longer supported"`},
+	},
+}
+
+// Introspect returns the introspection result of a server running the
+// graphql-go types generated from doc: its types, followed by the built in
+// scalars, scalars mapped with WithScalar and the introspection types, and
+// the directives graphql-go supports. Deprecations are included if the
+// generator honors @deprecated. The root operation types are those named
+// Query, Mutation and Subscription.
+func (g *Generator) Introspect(doc *Document) (*Introspection, error) {
+	if err := doc.validate(g.scalars); err != nil {
+		return nil, err
+	}
+	system, err := NewParser(strings.NewReader(introspectionTypes)).ParseDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	gen := *g
+	gen.doc = &Document{}
+	gen.doc.Types = append(append(gen.doc.Types, doc.Types...), system.Types...)
+	var schema IntrospectionSchema
+	for _, root := range []struct {
+		name string
+		ref  **IntrospectionTypeName
+	}{
+		{"Query", &schema.QueryType},
+		{"Mutation", &schema.MutationType},
+		{"Subscription", &schema.SubscriptionType},
+	} {
+		if doc.Type(root.name) != nil {
+			*root.ref = &IntrospectionTypeName{Name: root.name}
+		}
+	}
+	for i := range doc.Types {
+		typ, err := gen.introspectType(&doc.Types[i])
+		if err != nil {
+			return nil, err
+		}
+		schema.Types = append(schema.Types, *typ)
+	}
+	for _, name := range gen.undeclaredScalars(doc) {
+		schema.Types = append(schema.Types, IntrospectionType{Kind: "SCALAR", Name: name})
+	}
+	for i := range system.Types {
+		typ, err := gen.introspectType(&system.Types[i])
+		if err != nil {
+			return nil, err
+		}
+		schema.Types = append(schema.Types, *typ)
+	}
+	for _, directive := range introspectionDirectives {
+		schema.Directives = append(schema.Directives, IntrospectionDirective{
+			Name:        directive.name,
+			Description: optional(directive.description),
+			Locations:   directive.locations,
+			Args:        []IntrospectionInputValue{gen.introspectInputValue(&directive.arg)},
+		})
+	}
+	return &Introspection{Schema: schema}, nil
+}
+
+// IntrospectFiles reads the schema files at paths as one schema and writes
+// its introspection result to w as indented JSON.
+func (g *Generator) IntrospectFiles(w io.Writer, paths ...string) error {
+	doc, _, err := readFiles(paths)
+	if err != nil {
+		return err
+	}
+	result, err := g.Introspect(doc)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// undeclaredScalars returns the built in scalars and the names doc refers
+// to without declaring them, which the generator maps to Go expressions, in
+// order of first use.
+func (g *Generator) undeclaredScalars(doc *Document) []string {
+	names := append([]string(nil), builtinScalars...)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		seen[name] = true
+	}
+	use := func(typ gqlType) {
+		if name := typ.name(); !seen[name] && doc.Type(name) == nil {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for i := range doc.Types {
+		for _, field := range doc.Types[i].Variables {
+			use(fieldType(&field))
+			for _, arg := range field.Arg {
+				use(argType(&arg))
+			}
+		}
+	}
+	return names
+}
+
+// introspectType returns the introspection of a type definition.
+func (g *Generator) introspectType(obj *GqlModel) (*IntrospectionType, error) {
+	typ := &IntrospectionType{
+		Kind:        introspectionKinds[obj.Kind],
+		Name:        obj.Name,
+		Description: optional(obj.Description),
+	}
+	switch obj.Kind {
+	case KindObject, KindInterface:
+		typ.Fields = []IntrospectionField{}
+		for i := range obj.Variables {
+			field := &obj.Variables[i]
+			reason, deprecated, err := g.deprecationReason(obj.Name+"."+field.Name, field.Directives)
+			if err != nil {
+				return nil, err
+			}
+			f := IntrospectionField{
+				Name:         field.Name,
+				Description:  optional(field.Description),
+				Args:         []IntrospectionInputValue{},
+				Type:         g.typeRef(fieldType(field)),
+				IsDeprecated: deprecated,
+			}
+			if deprecated {
+				f.DeprecationReason = &reason
+			}
+			for j := range field.Arg {
+				f.Args = append(f.Args, g.introspectInputValue(&field.Arg[j]))
+			}
+			typ.Fields = append(typ.Fields, f)
+		}
+		typ.Interfaces = g.typeRefs(obj.Interfaces)
+		if obj.Kind == KindInterface {
+			typ.PossibleTypes = g.typeRefs(g.implementations(obj.Name))
+		}
+	case KindInput:
+		typ.InputFields = []IntrospectionInputValue{}
+		for i := range obj.Variables {
+			field := &obj.Variables[i]
+			typ.InputFields = append(typ.InputFields, g.introspectInputValue(&GqlArg{
+				Name:         field.Name,
+				Description:  field.Description,
+				Tok:          field.Tok,
+				Lit:          field.Lit,
+				Default:      field.Default,
+				Required:     field.Required,
+				List:         field.List,
+				ItemRequired: field.ItemRequired,
+			}))
+		}
+	case KindEnum:
+		typ.EnumValues = []IntrospectionEnumValue{}
+		for _, value := range obj.Values {
+			reason, deprecated, err := g.deprecationReason(obj.Name+"."+value.Name, value.Directives)
+			if err != nil {
+				return nil, err
+			}
+			v := IntrospectionEnumValue{
+				Name:         value.Name,
+				Description:  optional(value.Description),
+				IsDeprecated: deprecated,
+			}
+			if deprecated {
+				v.DeprecationReason = &reason
+			}
+			typ.EnumValues = append(typ.EnumValues, v)
+		}
+	case KindUnion:
+		typ.PossibleTypes = g.typeRefs(obj.Types)
+	}
+	return typ, nil
+}
+
+// introspectInputValue returns the introspection of an argument or input
+// field.
+func (g *Generator) introspectInputValue(arg *GqlArg) IntrospectionInputValue {
+	value := IntrospectionInputValue{
+		Name:        arg.Name,
+		Description: optional(arg.Description),
+		Type:        g.typeRef(argType(arg)),
+	}
+	if arg.Default != "" {
+		value.DefaultValue = &arg.Default
+	}
+	return value
+}
+
+// typeRef returns the introspection of a type reference.
+func (g *Generator) typeRef(typ gqlType) IntrospectionTypeRef {
+	ref := g.namedRef(typ.name())
+	if typ.list {
+		if typ.itemRequired {
+			ref = wrapRef("NON_NULL", ref)
+		}
+		ref = wrapRef("LIST", ref)
+	}
+	if typ.required {
+		ref = wrapRef("NON_NULL", ref)
+	}
+	return ref
+}
+
+// wrapRef returns a LIST or NON_NULL reference to the type of ref.
+func wrapRef(kind string, ref IntrospectionTypeRef) IntrospectionTypeRef {
+	return IntrospectionTypeRef{Kind: kind, OfType: &ref}
+}
+
+// namedRef returns a reference to the named type, which is a scalar unless
+// the document defines it.
+func (g *Generator) namedRef(name string) IntrospectionTypeRef {
+	kind := "SCALAR"
+	if def := g.doc.Type(name); def != nil {
+		kind = introspectionKinds[def.Kind]
+	}
+	return IntrospectionTypeRef{Kind: kind, Name: &name}
+}
+
+// typeRefs returns references to the named types, as an empty list rather
+// than null if there are none.
+func (g *Generator) typeRefs(names []string) []IntrospectionTypeRef {
+	refs := []IntrospectionTypeRef{}
+	for _, name := range names {
+		refs = append(refs, g.namedRef(name))
+	}
+	return refs
+}
+
+// implementations returns the names of the objects implementing the named
+// interface.
+func (g *Generator) implementations(iface string) []string {
+	var names []string
+	for _, obj := range g.doc.Types {
+		if obj.Kind != KindObject {
+			continue
+		}
+		for _, name := range obj.Interfaces {
+			if name == iface {
+				names = append(names, obj.Name)
+			}
+		}
+	}
+	return names
+}
+
+// optional returns a pointer to s, or nil if it is empty, for the nullable
+// strings of an introspection result.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ParseIntrospection reads an introspection result, such as one written by
+// IntrospectFiles or returned by a server with or without its "data"
+// envelope, and returns the schema it describes as the Parser would. The
+// built in scalars and introspection types are left out and deprecations
+// become @deprecated directives. name is the file the result is read from,
+// used in errors.
+func ParseIntrospection(name string, src io.Reader) (*Document, error) {
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Introspection
+		Data *Introspection `json:"data"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fileError(name, configError(data, err))
+	}
+	in := &result.Introspection
+	if result.Data != nil {
+		in = result.Data
+	}
+	if in.Schema.Types == nil {
+		return nil, fileError(name, fmt.Errorf("no __schema types"))
+	}
+	doc, err := in.document()
+	if err != nil {
+		return nil, fileError(name, err)
+	}
+	for i := range doc.Types {
+		doc.Types[i].File = name
+	}
+	return doc, nil
+}
+
+// fileError prefixes err with the name of the file it concerns, if known.
+func fileError(name string, err error) error {
+	if name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %v", name, err)
+}
+
+// ParseSchema parses the schema read from src, the content of the file
+// name: an introspection result if it holds JSON, as ParseIntrospection
+// reads it, and schema text otherwise.
+func ParseSchema(name string, src io.Reader) (*Document, error) {
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return ParseIntrospection(name, bytes.NewReader(data))
+	}
+	return ParseFile(name, bytes.NewReader(data))
+}
+
+// document returns the schema the introspection result describes.
+func (in *Introspection) document() (*Document, error) {
+	builtin := make(map[string]bool)
+	for _, name := range builtinScalars {
+		builtin[name] = true
+	}
+	doc := &Document{}
+	for _, typ := range in.Schema.Types {
+		if strings.HasPrefix(typ.Name, "__") || typ.Kind == "SCALAR" && builtin[typ.Name] {
+			continue
+		}
+		obj, err := typ.model()
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %v", typ.Name, err)
+		}
+		doc.Types = append(doc.Types, *obj)
+	}
+	return doc, nil
+}
+
+// model returns the definition of the type.
+func (typ *IntrospectionType) model() (*GqlModel, error) {
+	obj := &GqlModel{Name: typ.Name, Description: deref(typ.Description)}
+	found := false
+	for kind, name := range introspectionKinds {
+		if name == typ.Kind {
+			obj.Kind, found = kind, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown kind %q", typ.Kind)
+	}
+	for _, ref := range typ.Interfaces {
+		obj.Interfaces = append(obj.Interfaces, deref(ref.Name))
+	}
+	if obj.Kind == KindUnion {
+		for _, ref := range typ.PossibleTypes {
+			obj.Types = append(obj.Types, deref(ref.Name))
+		}
+	}
+	for _, field := range typ.Fields {
+		v := ModelVar{Name: field.Name, Description: deref(field.Description)}
+		t, err := field.Type.gqlType()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		v.Tok, v.Lit, v.List, v.ItemRequired, v.Required = t.tok, t.lit, t.list, t.itemRequired, t.required
+		for _, input := range field.Args {
+			arg, err := input.arg()
+			if err != nil {
+				return nil, fmt.Errorf("field %s: argument %s: %v", field.Name, input.Name, err)
+			}
+			v.Arg = append(v.Arg, *arg)
+		}
+		if field.IsDeprecated {
+			v.Directives = []Directive{deprecatedDirective(deref(field.DeprecationReason))}
+		}
+		obj.Variables = append(obj.Variables, v)
+	}
+	for _, input := range typ.InputFields {
+		arg, err := input.arg()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", input.Name, err)
+		}
+		obj.Variables = append(obj.Variables, ModelVar{
+			Name:         arg.Name,
+			Description:  arg.Description,
+			Tok:          arg.Tok,
+			Lit:          arg.Lit,
+			Required:     arg.Required,
+			List:         arg.List,
+			ItemRequired: arg.ItemRequired,
+			Default:      arg.Default,
+		})
+	}
+	for _, value := range typ.EnumValues {
+		v := EnumValue{Name: value.Name, Description: deref(value.Description)}
+		if value.IsDeprecated {
+			v.Directives = []Directive{deprecatedDirective(deref(value.DeprecationReason))}
+		}
+		obj.Values = append(obj.Values, v)
+	}
+	return obj, nil
+}
+
+// arg returns the argument or input field described by value.
+func (value *IntrospectionInputValue) arg() (*GqlArg, error) {
+	arg := &GqlArg{Name: value.Name, Description: deref(value.Description)}
+	t, err := value.Type.gqlType()
+	if err != nil {
+		return nil, err
+	}
+	arg.Tok, arg.Lit, arg.List, arg.ItemRequired, arg.Required = t.tok, t.lit, t.list, t.itemRequired, t.required
+	if value.DefaultValue != nil {
+		v, err := parseDefault(*value.DefaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default value %s: %v", *value.DefaultValue, err)
+		}
+		arg.Default = v.String()
+	}
+	return arg, nil
+}
+
+// gqlType returns the type ref refers to.
+func (ref *IntrospectionTypeRef) gqlType() (gqlType, error) {
+	var typ gqlType
+	if ref.Kind == "NON_NULL" && ref.OfType != nil {
+		typ.required, ref = true, ref.OfType
+	}
+	if ref.Kind == "LIST" && ref.OfType != nil {
+		typ.list, ref = true, ref.OfType
+		if ref.Kind == "NON_NULL" && ref.OfType != nil {
+			typ.itemRequired, ref = true, ref.OfType
+		}
+		if ref.Kind == "LIST" {
+			return typ, fmt.Errorf("nested lists are not supported")
+		}
+	}
+	if ref.Name == nil || ref.OfType != nil {
+		return typ, fmt.Errorf("invalid %s type reference", ref.Kind)
+	}
+	typ.lit = *ref.Name
+	if typ.tok = goScalars[typ.lit]; typ.tok == ILLEGAL {
+		typ.tok = IDENT
+	}
+	return typ, nil
+}
+
+// deref returns the string s points to, or "" for null.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,179 @@
+package graphqlgenerator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_Introspect(t *testing.T) {
+	schema := `"Something with a key."
+interface Node {
+  key: ID!
+}
+
+type User implements Node {
+  key: ID!
+  "The name of the user."
+  name: String @deprecated(reason: "Use fullName.")
+  joined: DateTime
+}
+
+enum Role {
+  ADMIN
+  ROOT @deprecated
+}
+
+input Filter {
+  roles: [Role!] = [ADMIN]
+}
+
+union Result = User
+
+type Query {
+  users(filter: Filter, "At most this many." first: Int = 10): [User!]!
+}
+`
+	doc, err := NewParser(strings.NewReader(schema)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewGenerator(WithScalar("DateTime", "graphql.DateTime")).Introspect(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Schema.QueryType == nil || result.Schema.QueryType.Name != "Query" || result.Schema.MutationType != nil {
+		t.Errorf("Introspect returned root types %+v, %+v", result.Schema.QueryType, result.Schema.MutationType)
+	}
+	types := make(map[string]string)
+	for _, typ := range result.Schema.Types {
+		data, err := json.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		types[typ.Name] = string(data)
+	}
+	tests := map[string]string{
+		"Node":     `{"kind":"INTERFACE","name":"Node","description":"Something with a key.","fields":[{"name":"key","description":null,"args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":null,"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"User","ofType":null}]}`,
+		"User":     `{"kind":"OBJECT","name":"User","description":null,"fields":[{"name":"key","description":null,"args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"name","description":"The name of the user.","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null},"isDeprecated":true,"deprecationReason":"Use fullName."},{"name":"joined","description":null,"args":[],"type":{"kind":"SCALAR","name":"DateTime","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":null,"interfaces":[{"kind":"INTERFACE","name":"Node","ofType":null}],"enumValues":null,"possibleTypes":null}`,
+		"Role":     `{"kind":"ENUM","name":"Role","description":null,"fields":null,"inputFields":null,"interfaces":null,"enumValues":[{"name":"ADMIN","description":null,"isDeprecated":false,"deprecationReason":null},{"name":"ROOT","description":null,"isDeprecated":true,"deprecationReason":"No longer supported"}],"possibleTypes":null}`,
+		"Filter":   `{"kind":"INPUT_OBJECT","name":"Filter","description":null,"fields":null,"inputFields":[{"name":"roles","description":null,"type":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Role","ofType":null}}},"defaultValue":"[ADMIN]"}],"interfaces":null,"enumValues":null,"possibleTypes":null}`,
+		"Result":   `{"kind":"UNION","name":"Result","description":null,"fields":null,"inputFields":null,"interfaces":null,"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"User","ofType":null}]}`,
+		"DateTime": `{"kind":"SCALAR","name":"DateTime","description":null,"fields":null,"inputFields":null,"interfaces":null,"enumValues":null,"possibleTypes":null}`,
+	}
+	for name, want := range tests {
+		if types[name] != want {
+			t.Errorf("Introspect returned type %s\n%s\ninstead of\n%s", name, types[name], want)
+		}
+	}
+	for _, name := range []string{"Int", "Float", "String", "Boolean", "ID", "__Schema", "__Type", "__TypeKind", "__Directive"} {
+		if types[name] == "" {
+			t.Errorf("Introspect did not return type %s", name)
+		}
+	}
+	var directives []string
+	for _, directive := range result.Schema.Directives {
+		directives = append(directives, directive.Name)
+	}
+	if strings.Join(directives, " ") != "include skip deprecated" {
+		t.Errorf("Introspect returned directives %v", directives)
+	}
+
+	if _, err := NewGenerator().Introspect(doc); err == nil || !strings.Contains(err.Error(), "DateTime") {
+		t.Errorf("Introspect returned %v for an unmapped scalar", err)
+	}
+}
+
+func Test_ParseIntrospection(t *testing.T) {
+	schema := `"Something with a key."
+interface Node {
+  key: ID!
+}
+
+type User implements Node {
+  key: ID!
+  "The name of the user."
+  name: String @deprecated(reason: "Use fullName.")
+  roles(
+    "Only these."
+    only: [Role!] = [ADMIN]
+  ): [Role]!
+}
+
+enum Role {
+  ADMIN
+  ROOT @deprecated
+}
+
+input Filter {
+  name: String = "a\"b"
+  first: Int! = 10
+}
+
+scalar Date
+
+union Result = User
+
+type Query {
+  users(filter: Filter): [User!]!
+  today: Date
+}
+`
+	doc, err := NewParser(strings.NewReader(schema)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	g := NewGenerator()
+	result, err := g.Introspect(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err = ParseIntrospection("schema.json", strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Types[0].File != "schema.json" {
+		t.Errorf("ParseIntrospection recorded file %q", doc.Types[0].File)
+	}
+	if err := Fprint(&b, doc); err != nil {
+		t.Error(err)
+	}
+	if b.String() != schema {
+		t.Errorf("ParseIntrospection returned\n%s\ninstead of\n%s", b.String(), schema)
+	}
+
+	// Generating from the result, enveloped as servers return it, matches
+	// generating from the schema but for the sum in the header.
+	code, err := GenerateToString(strings.NewReader("package models\n" + schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON strings.Builder
+	if err := NewGenerator(WithPackageName("models")).Generate(&fromJSON, strings.NewReader(`{"data": `+string(data)+`}`)); err != nil {
+		t.Fatal(err)
+	}
+	if body := func(code string) string { return code[strings.Index(code, "package "):] }; body(fromJSON.String()) != body(code) {
+		t.Errorf("generating from introspection returned\n%s\ninstead of\n%s", fromJSON.String(), code)
+	}
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`{"__schema": {"types": [{"kind": "OBJECT", "name": "Q", "fields": [{"name": "f", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "LIST", "ofType": {"kind": "SCALAR", "name": "Int"}}}}]}]}}`, "schema.json: type Q: field f: nested lists are not supported"},
+		{`{"__schema": {"types": [{"kind": "THING", "name": "Q"}]}}`, `schema.json: type Q: unknown kind "THING"`},
+		{`{"__schema": {"types": [{"kind": "INPUT_OBJECT", "name": "I", "inputFields": [{"name": "n", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "1 2"}]}]}}`, `schema.json: type I: field n: invalid default value 1 2: unexpected "2" after value`},
+		{`{"data": {}}`, "schema.json: no __schema types"},
+		{"{\n  \"__schema\": [\n}", "schema.json: 3:1: invalid character '}' looking for beginning of value"},
+	}
+	for _, test := range tests {
+		if _, err := ParseIntrospection("schema.json", strings.NewReader(test.src)); err == nil || err.Error() != test.err {
+			t.Errorf("ParseIntrospection returned %v instead of %s", err, test.err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package graphqlgenerator
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"strings"
 )
@@ -14,9 +15,11 @@ const (
 	ILLEGAL Token = iota
 	EOF
 	WS
+	COMMENT // # to the end of the line
 
 	// Literals
-	IDENT // main
+	IDENT  // main
+	NUMBER // 12, -3.5e2
 
 	// Misc characters
 	ASTERISK         // *
@@ -30,6 +33,9 @@ const (
 	BRACKETCLOSE     // )
 	COLON            //:
 	EQUAL            // =
+	AT               // @
+	AMPERSAND        // &
+	PIPE             // |
 	// Keywords
 	TYPE
 	STRING
@@ -38,6 +44,12 @@ const (
 	INT
 	ID
 	PACKAGE
+	INTERFACE
+	INPUT
+	ENUM
+	SCALAR
+	UNION
+	IMPLEMENTS
 )
 
 func TokenToString(tok Token) string {
@@ -48,8 +60,12 @@ func TokenToString(tok Token) string {
 		return "EOF"
 	case WS:
 		return "WS"
+	case COMMENT:
+		return "COMMENT"
 	case IDENT:
 		return "IDENT"
+	case NUMBER:
+		return "NUMBER"
 	case ASTERISK:
 		return "ASTERISK"
 	case COMMA:
@@ -68,6 +84,12 @@ func TokenToString(tok Token) string {
 		return "COLON"
 	case EQUAL:
 		return "EQUAL"
+	case AT:
+		return "AT"
+	case AMPERSAND:
+		return "AMPERSAND"
+	case PIPE:
+		return "PIPE"
 	case TYPE:
 		return "TYPE"
 	case STRING:
@@ -82,6 +104,18 @@ func TokenToString(tok Token) string {
 		return "ID"
 	case PACKAGE:
 		return "PACKAGE"
+	case INTERFACE:
+		return "INTERFACE"
+	case INPUT:
+		return "INPUT"
+	case ENUM:
+		return "ENUM"
+	case SCALAR:
+		return "SCALAR"
+	case UNION:
+		return "UNION"
+	case IMPLEMENTS:
+		return "IMPLEMENTS"
 	default:
 		return "Token not found in function TokenToString"
 	}
@@ -99,15 +133,34 @@ func isDigit(ch rune) bool {
 	return (ch >= '0' && ch <= '9')
 }
 
+// isKeyword reports whether tok is a keyword. Keywords are also valid names
+// wherever the grammar expects one, such as a field called id or type.
+func isKeyword(tok Token) bool {
+	return tok >= TYPE
+}
+
 var eof = rune(0)
 
+// Pos is a position in the schema source. Line and Column start at 1.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
 type Scanner struct {
-	r *bufio.Reader
+	r    *bufio.Reader
+	pos  Pos // position of the next rune
+	prev Pos // position before the last read, restored by unread
+	tok  Pos // position of the last scanned token
 }
 
 // NewScanner returns a new instance of Scanner.
 func NewScanner(r io.Reader) *Scanner {
-	return &Scanner{r: bufio.NewReader(r)}
+	return &Scanner{r: bufio.NewReader(r), pos: Pos{Line: 1, Column: 1}}
 }
 
 // read reads the next rune from the bufferred reader.
@@ -117,10 +170,22 @@ func (s *Scanner) read() rune {
 	if err != nil {
 		return eof
 	}
+	s.prev = s.pos
+	if ch == '\n' {
+		s.pos.Line++
+		s.pos.Column = 1
+	} else {
+		s.pos.Column++
+	}
 	return ch
 }
 
+// Pos returns the position of the token last returned by Scan.
+func (s *Scanner) Pos() Pos { return s.tok }
+
 func (s *Scanner) Scan() (tok Token, lit string) {
+	s.tok = s.pos
+
 	// Read the next rune.
 	ch := s.read()
 
@@ -129,7 +194,16 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 	if isWhitespace(ch) {
 		s.unread()
 		return s.scanWhitespace()
-	} else if isLetter(ch) || isDigit(ch) || ch == '"' {
+	} else if ch == '#' {
+		s.unread()
+		return s.scanComment()
+	} else if ch == '"' {
+		s.unread()
+		return s.scanString()
+	} else if isDigit(ch) || ch == '-' {
+		s.unread()
+		return s.scanNumber()
+	} else if isLetter(ch) || ch == '_' {
 		s.unread()
 		return s.scanIdent()
 	}
@@ -160,6 +234,12 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 		return BRACKETCLOSE, string(ch)
 	case '=':
 		return EQUAL, string(ch)
+	case '@':
+		return AT, string(ch)
+	case '&':
+		return AMPERSAND, string(ch)
+	case '|':
+		return PIPE, string(ch)
 	}
 
 	return ILLEGAL, string(ch)
@@ -187,6 +267,152 @@ func (s *Scanner) scanWhitespace() (tok Token, lit string) {
 	return WS, buf.String()
 }
 
+// scanComment consumes a comment up to, but not including, the end of the
+// line.
+func (s *Scanner) scanComment() (tok Token, lit string) {
+	var buf bytes.Buffer
+	for {
+		ch := s.read()
+		if ch == eof {
+			break
+		}
+		if ch == '\n' {
+			s.unread()
+			break
+		}
+		buf.WriteRune(ch)
+	}
+	return COMMENT, buf.String()
+}
+
+// scanString consumes a quoted string or a """block string""". It is
+// returned as an IDENT whose literal includes the quotes and any escape
+// sequences.
+func (s *Scanner) scanString() (tok Token, lit string) {
+	var buf bytes.Buffer
+	buf.WriteRune(s.read())
+
+	if ch := s.read(); ch == '"' {
+		buf.WriteRune(ch)
+		if ch = s.read(); ch != '"' {
+			// The empty string.
+			s.unread()
+			return IDENT, buf.String()
+		}
+		buf.WriteRune(ch)
+		return s.scanBlockString(&buf)
+	}
+	s.unread()
+
+	for {
+		ch := s.read()
+		if ch == eof || ch == '\n' {
+			return ILLEGAL, buf.String()
+		}
+		buf.WriteRune(ch)
+		if ch == '"' {
+			return IDENT, buf.String()
+		}
+		if ch == '\\' {
+			if ch = s.read(); ch == eof {
+				return ILLEGAL, buf.String()
+			}
+			buf.WriteRune(ch)
+		}
+	}
+}
+
+// scanBlockString consumes the rest of a block string, whose opening quotes
+// are in buf. Block strings may span lines; only \""" is escaped.
+func (s *Scanner) scanBlockString(buf *bytes.Buffer) (tok Token, lit string) {
+	quotes := 0
+	for {
+		ch := s.read()
+		if ch == eof {
+			return ILLEGAL, buf.String()
+		}
+		buf.WriteRune(ch)
+		switch {
+		case ch == '"':
+			if quotes++; quotes == 3 {
+				return IDENT, buf.String()
+			}
+		case ch == '\\' && s.peekQuotes():
+			quotes = 0
+			buf.WriteString(`"""`)
+			s.read()
+			s.read()
+			s.read()
+		default:
+			quotes = 0
+		}
+	}
+}
+
+// peekQuotes reports whether the next three runes are quotes, leaving them
+// unread.
+func (s *Scanner) peekQuotes() bool {
+	next, err := s.r.Peek(3)
+	return err == nil && string(next) == `"""`
+}
+
+// scanNumber consumes an integer or floating point number: an optional minus
+// sign, digits, an optional fraction and an optional exponent.
+func (s *Scanner) scanNumber() (tok Token, lit string) {
+	var buf bytes.Buffer
+	if ch := s.read(); ch == '-' {
+		buf.WriteRune(ch)
+	} else {
+		s.unread()
+	}
+	if !s.scanDigits(&buf) {
+		return ILLEGAL, buf.String()
+	}
+	if ch := s.read(); ch == '.' {
+		buf.WriteRune(ch)
+		if !s.scanDigits(&buf) {
+			return ILLEGAL, buf.String()
+		}
+	} else {
+		s.unread()
+	}
+	if ch := s.read(); ch == 'e' || ch == 'E' {
+		buf.WriteRune(ch)
+		if ch = s.read(); ch == '+' || ch == '-' {
+			buf.WriteRune(ch)
+		} else {
+			s.unread()
+		}
+		if !s.scanDigits(&buf) {
+			return ILLEGAL, buf.String()
+		}
+	} else {
+		s.unread()
+	}
+	// A number must not run into a name, as in 12ab.
+	if ch := s.read(); isLetter(ch) || ch == '_' {
+		buf.WriteRune(ch)
+		return ILLEGAL, buf.String()
+	}
+	s.unread()
+	return NUMBER, buf.String()
+}
+
+// scanDigits consumes contiguous digits into buf, reporting whether there
+// were any.
+func (s *Scanner) scanDigits(buf *bytes.Buffer) bool {
+	n := 0
+	for {
+		ch := s.read()
+		if !isDigit(ch) {
+			s.unread()
+			return n > 0
+		}
+		buf.WriteRune(ch)
+		n++
+	}
+}
+
 // scanIdent consumes the current rune and all contiguous ident runes.
 func (s *Scanner) scanIdent() (tok Token, lit string) {
 	// Create a buffer and read the current character into it.
@@ -222,6 +448,18 @@ func (s *Scanner) scanIdent() (tok Token, lit string) {
 		return ID, buf.String()
 	case "PACKAGE":
 		return PACKAGE, buf.String()
+	case "INTERFACE":
+		return INTERFACE, buf.String()
+	case "INPUT":
+		return INPUT, buf.String()
+	case "ENUM":
+		return ENUM, buf.String()
+	case "SCALAR":
+		return SCALAR, buf.String()
+	case "UNION":
+		return UNION, buf.String()
+	case "IMPLEMENTS":
+		return IMPLEMENTS, buf.String()
 
 	}
 
@@ -229,4 +467,9 @@ func (s *Scanner) scanIdent() (tok Token, lit string) {
 	return IDENT, buf.String()
 }
 
-func (s *Scanner) unread() { _ = s.r.UnreadRune() } // puts rune back on reader
+// unread puts the last read rune back on the reader.
+func (s *Scanner) unread() {
+	if s.r.UnreadRune() == nil {
+		s.pos = s.prev
+	}
+}
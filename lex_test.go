@@ -43,99 +43,99 @@ func Test_Scan(t *testing.T) {
 
 	check := scanHelper(TYPE, "type", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(IDENT, "Query", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(CURLBRACKETOPEN, "{", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, "\n  ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(IDENT, "performance", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(BRACKETOPEN, "(", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(IDENT, "word", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(COLON, ":", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(INT, "int", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(EQUAL, "=", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(IDENT, `"100"`, s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(EXCLAMATION, `!`, s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(BRACKETCLOSE, ")", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(COLON, ":", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, " ", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(IDENT, "PerformanceSummary", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(EXCLAMATION, "!", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(WS, "\n", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 	check = scanHelper(CURLBRACKETCLOSE, "}", s)
 	if check != nil {
-		t.Errorf(check.Error())
+		t.Error(check)
 	}
 
 }
@@ -152,3 +152,56 @@ func scanHelper(tok Token, lit string, s *Scanner) error {
 	}
 	return nil
 }
+
+func Test_ScanNumber(t *testing.T) {
+	tests := []struct {
+		src string
+		tok Token
+		lit string
+	}{
+		{"100", NUMBER, "100"},
+		{"-3", NUMBER, "-3"},
+		{"1.5e-3,", NUMBER, "1.5e-3"},
+		{"2E10]", NUMBER, "2E10"},
+		{"1.", ILLEGAL, "1."},
+		{"-", ILLEGAL, "-"},
+		{"12ab", ILLEGAL, "12a"},
+	}
+	for _, test := range tests {
+		s := NewScanner(strings.NewReader(test.src))
+		if err := scanHelper(test.tok, test.lit, s); err != nil {
+			t.Errorf("%q: %v", test.src, err)
+		}
+	}
+}
+
+func Test_ScanBlockString(t *testing.T) {
+	cases := []struct{ input, lit string }{
+		{`"" x`, `""`},
+		{`"""""" x`, `""""""`},
+		{"\"\"\"a\n  \"b\"\n\"\"\" x", "\"\"\"a\n  \"b\"\n\"\"\""},
+		{`"""say \""" "" """ x`, `"""say \""" "" """`},
+	}
+	for _, c := range cases {
+		s := NewScanner(strings.NewReader(c.input))
+		if tok, lit := s.Scan(); tok != IDENT || lit != c.lit {
+			t.Errorf("Scan returned %v %q for %q instead of IDENT %q", tok, lit, c.input, c.lit)
+		}
+	}
+	if tok, _ := NewScanner(strings.NewReader(`"""open`)).Scan(); tok != ILLEGAL {
+		t.Errorf("Scan returned %v for an unterminated block string instead of ILLEGAL", tok)
+	}
+}
+
+func Test_ScanComment(t *testing.T) {
+	s := NewScanner(strings.NewReader("# a \"comment\"\ntype"))
+	if err := scanHelper(COMMENT, `# a "comment"`, s); err != nil {
+		t.Error(err)
+	}
+	if err := scanHelper(WS, "\n", s); err != nil {
+		t.Error(err)
+	}
+	if err := scanHelper(TYPE, "type", s); err != nil {
+		t.Error(err)
+	}
+}
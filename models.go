@@ -0,0 +1,267 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultModelTypes maps the built in scalars to the Go types of model
+// fields.
+var defaultModelTypes = map[string]string{
+	"String":  "string",
+	"Float":   "float64",
+	"Int":     "int",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+// initialisms are the words spelled in upper case in Go field and constant
+// names, so that a field user_id becomes UserID.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "SQL": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// camelName joins the words of name, split at underscores and at lower to
+// upper case changes, into an exported Go identifier. If lower is set the
+// words are first put in lower case, as for enum values such as SUPER_USER.
+func camelName(name string, lower bool) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if lower {
+			part = strings.ToLower(part)
+		}
+		start := 0
+		for i, r := range part {
+			if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(part[i-1])) {
+				b.WriteString(camelWord(part[start:i]))
+				start = i
+			}
+		}
+		b.WriteString(camelWord(part[start:]))
+	}
+	return b.String()
+}
+
+// camelWord returns word exported, or in upper case if it is an initialism.
+func camelWord(word string) string {
+	if initialisms[strings.ToUpper(word)] {
+		return strings.ToUpper(word)
+	}
+	return exportName(word)
+}
+
+// modelName returns the Go name of the model of the named type.
+func modelName(name string) string {
+	return exportName(name)
+}
+
+// enumConst returns the name of the model constant of the value of the
+// named enum.
+func enumConst(enum string, value string) string {
+	return modelName(enum) + camelName(value, true)
+}
+
+// markerMethod returns the name of the method marking models as members of
+// the interface or union named name.
+func markerMethod(name string) string {
+	return "Is" + modelName(name)
+}
+
+// modelNames returns the Go names the models of obj declare at package
+// level, with their owners described for error messages.
+func modelNames(obj *GqlModel) (names []string, owners []string) {
+	names = append(names, modelName(obj.Name))
+	owners = append(owners, fmt.Sprintf("the model of %s %s", obj.Kind, obj.Name))
+	for _, value := range obj.Values {
+		names = append(names, enumConst(obj.Name, value.Name))
+		owners = append(owners, fmt.Sprintf("the constant of %s.%s", obj.Name, value.Name))
+	}
+	return names, owners
+}
+
+// modelType returns the Go type of a model field of type typ in the model of
+// owner, or of an argument or result if owner is "". Nullable fields are
+// pointers except for interfaces, unions, lists and scalars without a model
+// type, which are nil when null already. Non-null fields are values unless
+// the value would contain the model of owner.
+func (g *Generator) modelType(owner string, typ gqlType) (ast.Expr, error) {
+	name := typ.name()
+	var elt ast.Expr
+	pointer := true
+	if goType, ok := g.modelTypes[name]; ok {
+		expr, err := parser.ParseExpr(goType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Go type %q for %s: %v", goType, name, err)
+		}
+		elt = expr
+	} else if def := g.doc.Type(name); def != nil && def.Kind != KindScalar {
+		elt = ast.NewIdent(modelName(name))
+		pointer = def.Kind != KindInterface && def.Kind != KindUnion
+	} else {
+		elt = emptyInterface()
+		pointer = false
+	}
+	ptr := func(x ast.Expr, required bool) ast.Expr {
+		if pointer && !required {
+			return &ast.StarExpr{X: x}
+		}
+		return x
+	}
+	if typ.list {
+		return &ast.ArrayType{Elt: ptr(elt, typ.itemRequired)}, nil
+	}
+	return ptr(elt, typ.required && !g.byPointer(owner, name)), nil
+}
+
+// byPointer reports whether a non-null field of the named type in the model
+// of owner holds a pointer: when the model of the type holds that of owner
+// by value, directly or through other models, so that the struct would
+// contain itself.
+func (g *Generator) byPointer(owner string, name string) bool {
+	return owner != "" && (name == owner || g.embedded[name][owner])
+}
+
+// embeddedTypes returns the object and input types whose models the model
+// of each type of doc holds by value, directly or through other models:
+// those of its non-null fields that are not lists.
+func (g *Generator) embeddedTypes(doc *Document) map[string]map[string]bool {
+	refs := make(map[string][]string)
+	for _, obj := range doc.Types {
+		for i := range obj.Variables {
+			typ := fieldType(&obj.Variables[i])
+			if typ.list || !typ.required {
+				continue
+			}
+			if _, ok := g.modelTypes[typ.name()]; ok {
+				continue
+			}
+			if def := doc.Type(typ.name()); def != nil && (def.Kind == KindObject || def.Kind == KindInput) {
+				refs[obj.Name] = append(refs[obj.Name], def.Name)
+			}
+		}
+	}
+	embedded := make(map[string]map[string]bool)
+	for name := range refs {
+		seen := make(map[string]bool)
+		var visit func(from string)
+		visit = func(from string) {
+			for _, to := range refs[from] {
+				if !seen[to] {
+					seen[to] = true
+					visit(to)
+				}
+			}
+		}
+		visit(name)
+		embedded[name] = seen
+	}
+	return embedded
+}
+
+// modelDecls returns the declarations of the Go model of obj.
+func (g *Generator) modelDecls(obj GqlModel) ([]ast.Decl, error) {
+	name := modelName(obj.Name)
+	switch obj.Kind {
+	case KindObject, KindInput:
+		fields := &ast.FieldList{}
+		seen := make(map[string]string)
+		var members []string
+		markers := make(map[string]string)
+		if obj.Kind == KindObject {
+			members = g.memberOf(obj)
+			for _, iface := range members {
+				markers[markerMethod(iface)] = iface
+			}
+		}
+		for _, element := range obj.Variables {
+			fieldName := camelName(element.Name, false)
+			if other, ok := seen[fieldName]; ok {
+				return nil, &ValidationError{File: obj.File, Pos: element.Pos,
+					Msg: fmt.Sprintf("fields %s and %s of %s %s both generate the Go field %s", other, element.Name, obj.Kind, obj.Name, fieldName)}
+			}
+			if iface, ok := markers[fieldName]; ok {
+				return nil, &ValidationError{File: obj.File, Pos: element.Pos,
+					Msg: fmt.Sprintf("field %s of %s %s and the marker method of %s both generate the Go name %s", element.Name, obj.Kind, obj.Name, iface, fieldName)}
+			}
+			seen[fieldName] = element.Name
+			typ, err := g.modelType(obj.Name, fieldType(&element))
+			if err != nil {
+				return nil, err
+			}
+			fields.List = append(fields.List, &ast.Field{
+				Names: []*ast.Ident{ast.NewIdent(fieldName)},
+				Type:  typ,
+				Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:" + strconv.Quote(element.Name) + "`"},
+			})
+		}
+		decls := []ast.Decl{goTypeDecl(name, &ast.StructType{Fields: fields})}
+		for _, iface := range members {
+			decls = append(decls, markerDecl(name, iface))
+		}
+		return decls, nil
+	case KindInterface, KindUnion:
+		methods := &ast.FieldList{}
+		for _, iface := range obj.Interfaces {
+			methods.List = append(methods.List, &ast.Field{Type: ast.NewIdent(modelName(iface))})
+		}
+		methods.List = append(methods.List, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(markerMethod(obj.Name))},
+			Type:  &ast.FuncType{Params: &ast.FieldList{}},
+		})
+		return []ast.Decl{goTypeDecl(name, &ast.InterfaceType{Methods: methods})}, nil
+	case KindEnum:
+		values := &ast.GenDecl{Tok: token.CONST}
+		for _, value := range obj.Values {
+			values.Specs = append(values.Specs, &ast.ValueSpec{
+				Names:  []*ast.Ident{ast.NewIdent(enumConst(obj.Name, value.Name))},
+				Type:   ast.NewIdent(name),
+				Values: []ast.Expr{stringLit(value.Name)},
+			})
+		}
+		return []ast.Decl{goTypeDecl(name, ast.NewIdent("string")), values}, nil
+	}
+	return nil, nil
+}
+
+// memberOf returns the names of the interfaces and unions whose marker
+// methods the model of the object obj implements.
+func (g *Generator) memberOf(obj GqlModel) []string {
+	names := append([]string(nil), obj.Interfaces...)
+	for _, union := range g.doc.Types {
+		if union.Kind != KindUnion {
+			continue
+		}
+		for _, member := range union.Types {
+			if member == obj.Name {
+				names = append(names, union.Name)
+			}
+		}
+	}
+	return names
+}
+
+// goTypeDecl returns the declaration of the Go type name.
+func goTypeDecl(name string, typ ast.Expr) ast.Decl {
+	return &ast.GenDecl{
+		Tok:   token.TYPE,
+		Specs: []ast.Spec{&ast.TypeSpec{Name: ast.NewIdent(name), Type: typ}},
+	}
+}
+
+// markerDecl returns the marker method of the interface or union iface on
+// the model name.
+func markerDecl(name string, iface string) ast.Decl {
+	return &ast.FuncDecl{
+		Recv: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent(name)}}},
+		Name: ast.NewIdent(markerMethod(iface)),
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+		Body: &ast.BlockStmt{},
+	}
+}
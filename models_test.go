@@ -0,0 +1,139 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_CamelName(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower bool
+		want  string
+	}{
+		{"name", false, "Name"},
+		{"id", false, "ID"},
+		{"user_id", false, "UserID"},
+		{"homeUrl", false, "HomeURL"},
+		{"createdAt", false, "CreatedAt"},
+		{"SUPER_USER", true, "SuperUser"},
+		{"ADMIN", true, "Admin"},
+	}
+	for _, test := range tests {
+		if got := camelName(test.name, test.lower); got != test.want {
+			t.Errorf("camelName(%q, %v) returned %s instead of %s", test.name, test.lower, got, test.want)
+		}
+	}
+}
+
+func Test_GenerateModels(t *testing.T) {
+	testString := `package models
+interface Node { id: ID! }
+type User implements Node {
+  id: ID!
+  friends: [User!]!
+  best: User
+  manager: User!
+  address: Address!
+  role: Role
+  joined: DateTime
+  extra: JSON
+}
+type Address { city: String! }
+type Team { lead: Member! }
+type Member { team: Team! }
+input Filter { tags: [String] }
+enum Role { ADMIN SUPER_USER }
+scalar DateTime
+scalar JSON
+union Result = User`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString), WithMode(ModeModels),
+		WithModelType("DateTime", "time.Time"), WithImport("time", "time"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package models
+
+import "time"
+
+type Node interface {
+	IsNode()
+}
+
+type User struct {
+	ID      string      ` + "`json:\"id\"`" + `
+	Friends []User      ` + "`json:\"friends\"`" + `
+	Best    *User       ` + "`json:\"best\"`" + `
+	Manager *User       ` + "`json:\"manager\"`" + `
+	Address Address     ` + "`json:\"address\"`" + `
+	Role    *Role       ` + "`json:\"role\"`" + `
+	Joined  *time.Time  ` + "`json:\"joined\"`" + `
+	Extra   interface{} ` + "`json:\"extra\"`" + `
+}
+
+func (User) IsNode() {}
+
+func (User) IsResult() {}
+
+type Address struct {
+	City string ` + "`json:\"city\"`" + `
+}
+
+type Team struct {
+	Lead *Member ` + "`json:\"lead\"`" + `
+}
+
+type Member struct {
+	Team *Team ` + "`json:\"team\"`" + `
+}
+
+type Filter struct {
+	Tags []*string ` + "`json:\"tags\"`" + `
+}
+
+type Role string
+
+const (
+	RoleAdmin     Role = "ADMIN"
+	RoleSuperUser Role = "SUPER_USER"
+)
+
+type Result interface {
+	IsResult()
+}
+`
+	out := b.String()
+	if i := strings.Index(out, "package models"); i < 0 || out[i:] != want {
+		t.Errorf("Generate returned\n%s\ninstead of\n%s", out, want)
+	}
+}
+
+func Test_GenerateModelsCollisions(t *testing.T) {
+	testString := `package models
+type User { key: String }
+type UserType { key: String }`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString), WithMode(ModeAll))
+	if err == nil || err.Error() != "3:1: type User and the model of type UserType both generate the Go name UserType" {
+		t.Errorf("Generate did not report the model colliding with the type, returned %v", err)
+	}
+	if err := Generate(&b, strings.NewReader(testString), WithMode(ModeAll), WithNamePrefix("Gql")); err != nil {
+		t.Error(err)
+	}
+
+	testString = `package models
+type User { user_id: String, userID: String }`
+	err = Generate(&b, strings.NewReader(testString), WithMode(ModeModels))
+	if err == nil || err.Error() != "2:30: fields user_id and userID of type User both generate the Go field UserID" {
+		t.Errorf("Generate did not report the colliding fields, returned %v", err)
+	}
+
+	testString = `package models
+interface Node { isNode: Boolean }
+type User implements Node { isNode: Boolean }`
+	err = Generate(&b, strings.NewReader(testString), WithMode(ModeModels))
+	if err == nil || err.Error() != "3:29: field isNode of type User and the marker method of Node both generate the Go name IsNode" {
+		t.Errorf("Generate did not report the field colliding with the marker method, returned %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// exportName returns name with its first letter in upper case, dropping
+// leading underscores, which would leave the identifier unexported.
+func exportName(name string) string {
+	name = strings.TrimLeft(name, "_")
+	r, size := utf8.DecodeRuneInString(name)
+	if size == 0 {
+		return ""
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// goName returns the Go identifier of the value generated for the named
+// GraphQL type: the name exported and wrapped in the configured prefix and
+// suffix, so that a type named type becomes Type, or TypeType with the
+// suffix "Type". The GraphQL name itself is left as it is.
+func (g *Generator) goName(name string) string {
+	if goName, ok := g.names[name]; ok {
+		return goName
+	}
+	if g.namePrefix == "" {
+		return exportName(name) + g.nameSuffix
+	}
+	return exportName(g.namePrefix) + exportName(name) + g.nameSuffix
+}
+
+// goNames returns the Go identifier of each type of doc referenced by name
+// rather than through the scalar mapping. It reports names that are not
+// valid Go identifiers or that two declarations would share, including the
+// models generated in ModeModels and ModeAll and the resolvers.
+func (g *Generator) goNames(doc *Document) (map[string]string, error) {
+	names := make(map[string]string)
+	owners := make(map[string]string)
+	var errs ValidationErrors
+	declare := func(obj *GqlModel, goName string, owner string) {
+		if !token.IsIdentifier(goName) || token.IsKeyword(goName) {
+			errs = append(errs, &ValidationError{File: obj.File, Pos: obj.Pos,
+				Msg: fmt.Sprintf("%s has no valid Go name, found %q", owner, goName)})
+			return
+		}
+		if other, ok := owners[goName]; ok {
+			errs = append(errs, &ValidationError{File: obj.File, Pos: obj.Pos,
+				Msg: fmt.Sprintf("%s and %s both generate the Go name %s", other, owner, goName)})
+			return
+		}
+		owners[goName] = owner
+	}
+	for i := range doc.Types {
+		obj := &doc.Types[i]
+		if _, ok := g.scalars[obj.Name]; ok {
+			continue
+		}
+		goName := g.goName(obj.Name)
+		names[obj.Name] = goName
+		if g.mode != ModeModels {
+			declare(obj, goName, fmt.Sprintf("%s %s", obj.Kind, obj.Name))
+		}
+	}
+	if g.mode != ModeSchema {
+		for i := range doc.Types {
+			obj := &doc.Types[i]
+			if obj.Kind == KindScalar {
+				continue
+			}
+			names, owners := modelNames(obj)
+			for j, goName := range names {
+				declare(obj, goName, owners[j])
+			}
+		}
+	}
+	if g.resolvers {
+		var first *GqlModel
+		for i := range doc.Types {
+			obj := &doc.Types[i]
+			names, owners := g.resolverNames(obj)
+			if len(names) > 0 && first == nil {
+				first = obj
+			}
+			for j, goName := range names {
+				declare(obj, goName, owners[j])
+			}
+		}
+		if first != nil {
+			declare(first, "Resolver", "the root resolver interface")
+			declare(first, "resolver", "the root resolver variable")
+			declare(first, "SetResolver", "the root resolver setter")
+			for _, name := range g.decodedTypes(doc) {
+				declare(first, "decode"+modelName(name), "the decoder of type "+name)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return names, nil
+}
@@ -0,0 +1,69 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_GoName(t *testing.T) {
+	tests := []struct {
+		prefix string
+		suffix string
+		name   string
+		want   string
+	}{
+		{"", "", "User", "User"},
+		{"", "", "type", "Type"},
+		{"", "", "graphql", "Graphql"},
+		{"", "", "_user", "User"},
+		{"", "Type", "User", "UserType"},
+		{"gql", "", "user", "GqlUser"},
+	}
+	for _, test := range tests {
+		g := NewGenerator(WithNamePrefix(test.prefix), WithNameSuffix(test.suffix))
+		if got := g.goName(test.name); got != test.want {
+			t.Errorf("goName(%q) with prefix %q and suffix %q returned %s instead of %s", test.name, test.prefix, test.suffix, got, test.want)
+		}
+	}
+}
+
+func Test_GenerateGoNames(t *testing.T) {
+	testString := `package models
+type func implements node {
+  key: String
+  next: func
+}
+interface node {
+  key: String
+}
+union result = func`
+	var b strings.Builder
+	if err := Generate(&b, strings.NewReader(testString), WithNameSuffix("Type")); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"var FuncType = graphql.NewObject(",
+		`Name:       "func",`,
+		"Interfaces: []*graphql.Interface{NodeType},",
+		"Type: FuncType,",
+		"Types: []*graphql.Object{FuncType},",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Generate output is missing %q, returned %s", want, out)
+		}
+	}
+
+	testString = `package models
+type user { key: String }
+type User { key: String }`
+	err := Generate(&b, strings.NewReader(testString))
+	if err == nil || err.Error() != "3:1: type user and type User both generate the Go name User" {
+		t.Errorf("Generate did not report the Go name collision, returned %v", err)
+	}
+
+	err = Generate(&b, strings.NewReader(testString), WithNameSuffix("-"))
+	if err == nil || !strings.Contains(err.Error(), `type user has no valid Go name, found "User-"`) {
+		t.Errorf("Generate did not report the invalid Go name, returned %v", err)
+	}
+}
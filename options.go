@@ -0,0 +1,287 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Style selects how the generated graphql-go objects are laid out. In
+// either style, a type whose fields refer back to it, directly or through
+// other types, is declared without fields and has them added by an init
+// function.
+type Style int
+
+const (
+	// StyleVar declares each object as a package variable with its fields
+	// listed inline.
+	StyleVar Style = iota
+	// StyleThunk wraps each object's fields in a graphql.FieldsThunk,
+	// deferring them until the schema is built.
+	StyleThunk
+)
+
+// String returns the name of the style as accepted by ParseStyle.
+func (s Style) String() string {
+	switch s {
+	case StyleVar:
+		return "var"
+	case StyleThunk:
+		return "thunk"
+	}
+	return fmt.Sprintf("Style(%d)", int(s))
+}
+
+// ParseStyle returns the style with the given name, "var" or "thunk".
+func ParseStyle(name string) (Style, error) {
+	switch name {
+	case "var":
+		return StyleVar, nil
+	case "thunk":
+		return StyleThunk, nil
+	}
+	return 0, fmt.Errorf("unknown style %q, expected var or thunk", name)
+}
+
+// Mode selects what the generator emits.
+type Mode int
+
+const (
+	// ModeSchema generates the graphql-go type definitions.
+	ModeSchema Mode = iota
+	// ModeModels generates Go models of the schema types: structs for
+	// objects and input types, string constants for enums and interfaces
+	// for interfaces and unions.
+	ModeModels
+	// ModeAll generates both the type definitions and the models. Unless
+	// a name prefix or suffix is set, the type definitions take the suffix
+	// "Type", so that type User gives UserType and the model User.
+	ModeAll
+)
+
+// String returns the name of the mode as accepted by ParseMode.
+func (m Mode) String() string {
+	switch m {
+	case ModeSchema:
+		return "schema"
+	case ModeModels:
+		return "models"
+	case ModeAll:
+		return "all"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode returns the mode with the given name, "schema", "models" or
+// "all".
+func ParseMode(name string) (Mode, error) {
+	switch name {
+	case "schema":
+		return ModeSchema, nil
+	case "models":
+		return ModeModels, nil
+	case "all":
+		return ModeAll, nil
+	}
+	return 0, fmt.Errorf("unknown mode %q, expected schema, models or all", name)
+}
+
+// defaultScalars maps the built in GraphQL scalars to their graphql-go types.
+var defaultScalars = map[string]string{
+	"String":  "graphql.String",
+	"Float":   "graphql.Float",
+	"Int":     "graphql.Int",
+	"Boolean": "graphql.Boolean",
+	"ID":      "graphql.String",
+}
+
+// graphqlPath is the import path of the graphql-go package.
+const graphqlPath = "github.com/graphql-go/graphql"
+
+// supportedDirectives are the directives the generator can honor.
+var supportedDirectives = map[string]bool{
+	"deprecated": true,
+}
+
+// Generator turns GraphQL schemas into graphql-go type definitions.
+type Generator struct {
+	packageName string
+	scalars     map[string]string
+	header      string
+	sources     []string
+	style       Style
+	templates   *template.Template
+	directives  map[string]bool
+	namePrefix  string
+	nameSuffix  string
+	imports     map[string]string
+	mode        Mode
+	modelTypes  map[string]string
+	resolvers   bool
+
+	// doc is the document being generated, names the Go names of its types,
+	// cyclic the types whose fields are added in init and embedded the
+	// models each model holds by value, set by generate.
+	doc      *Document
+	names    map[string]string
+	cyclic   map[string]bool
+	embedded map[string]map[string]bool
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// NewGenerator returns a new instance of Generator configured by opts.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{
+		scalars:    make(map[string]string),
+		directives: map[string]bool{"deprecated": true},
+		imports: map[string]string{
+			"graphql": graphqlPath,
+			"context": "context",
+			"errors":  "errors",
+			"fmt":     "fmt",
+		},
+		modelTypes: make(map[string]string),
+	}
+	for name, goType := range defaultScalars {
+		g.scalars[name] = goType
+	}
+	for name, goType := range defaultModelTypes {
+		g.modelTypes[name] = goType
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// WithPackageName sets the package clause of the generated file, overriding
+// any package declared in the schema.
+func WithPackageName(name string) Option {
+	return func(g *Generator) { g.packageName = name }
+}
+
+// WithScalar maps the GraphQL type name to the Go expression used wherever
+// the type is referenced, e.g. WithScalar("ID", "graphql.ID"). The package
+// of the expression may be given by its import path, as in
+// WithScalar("DateTime", "github.com/acme/scalars.DateTime"), which imports
+// the package where the type is used.
+func WithScalar(name string, goType string) Option {
+	return func(g *Generator) { g.scalars[name] = g.importExpr(goType) }
+}
+
+// importExpr records the import path a Go expression may start with, as
+// in github.com/acme/scalars.DateTime, and returns the expression referring
+// to the package by name. The name is the last element of the path without
+// its major version, as in github.com/acme/time/v2 or gopkg.in/yaml.v3;
+// packages named otherwise are declared with WithImport.
+func (g *Generator) importExpr(expr string) string {
+	i := strings.LastIndex(expr, "/")
+	if i < 0 {
+		return expr
+	}
+	dot := strings.Index(expr[i:], ".")
+	if dot < 0 {
+		return expr
+	}
+	path, name, sel := expr[:i+dot], expr[i+1:i+dot], expr[i+dot+1:]
+	if strings.HasPrefix(path, "gopkg.in/") {
+		if dot := strings.Index(sel, "."); dot >= 0 && isMajorVersion(sel[:dot]) {
+			path, sel = path+"."+sel[:dot], sel[dot+1:]
+		}
+	} else if isMajorVersion(name) {
+		name = expr[strings.LastIndex(expr[:i], "/")+1 : i]
+	}
+	g.imports[name] = path
+	return name + "." + sel
+}
+
+// isMajorVersion reports whether elem is a major version suffix of an
+// import path, such as v2.
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for _, c := range elem[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithImport declares the import path of the package that Go expressions
+// given to the generator, such as scalar mappings, refer to by name. The
+// package is imported only by output that uses it.
+func WithImport(name string, path string) Option {
+	return func(g *Generator) { g.imports[name] = path }
+}
+
+// WithHeader sets text written as a comment at the top of the generated file.
+func WithHeader(text string) Option {
+	return func(g *Generator) { g.header = text }
+}
+
+// WithSources records the paths of the schema files in the generated header.
+func WithSources(paths ...string) Option {
+	return func(g *Generator) { g.sources = append(g.sources, paths...) }
+}
+
+// WithStyle sets the layout of the generated objects.
+func WithStyle(style Style) Option {
+	return func(g *Generator) { g.style = style }
+}
+
+// WithTemplates renders the output through the "object", "field" and "arg"
+// templates of t instead of the built in code generator. DefaultTemplates
+// returns the built in set as a starting point.
+func WithTemplates(t *template.Template) Option {
+	return func(g *Generator) { g.templates = t }
+}
+
+// WithDirectives sets the directives the generator honors, replacing the
+// default of "deprecated". Directives that are not honored are ignored.
+func WithDirectives(names ...string) Option {
+	return func(g *Generator) {
+		g.directives = make(map[string]bool)
+		for _, name := range names {
+			g.directives[name] = true
+		}
+	}
+}
+
+// WithNamePrefix sets a prefix of the Go variable generated for each type,
+// e.g. WithNamePrefix("Gql") names the value for type User GqlUser.
+func WithNamePrefix(prefix string) Option {
+	return func(g *Generator) { g.namePrefix = prefix }
+}
+
+// WithNameSuffix sets a suffix of the Go variable generated for each type,
+// e.g. WithNameSuffix("Type") names the value for type User UserType.
+func WithNameSuffix(suffix string) Option {
+	return func(g *Generator) { g.nameSuffix = suffix }
+}
+
+// WithMode sets what the generator emits, by default ModeSchema.
+func WithMode(mode Mode) Option {
+	return func(g *Generator) { g.mode = mode }
+}
+
+// WithModelType sets the Go type of model fields of the named scalar, e.g.
+// WithModelType("DateTime", "time.Time"). As with WithScalar, the package
+// may be given by its import path. Fields of scalars without a model type
+// are interface{}.
+func WithModelType(name string, goType string) Option {
+	return func(g *Generator) { g.modelTypes[name] = g.importExpr(goType) }
+}
+
+// WithResolvers wires each field that is not read from the models, the
+// fields of Query, Mutation and Subscription and those taking arguments, to
+// a generated resolver interface, such as QueryResolver, which the program
+// installs with SetResolver. Arguments are decoded into an args struct per
+// field by generated functions, which report values of the wrong type.
+// Resolvers return models, so they require ModeAll.
+func WithResolvers() Option {
+	return func(g *Generator) { g.resolvers = true }
+}
@@ -3,28 +3,162 @@ package graphqlgenerator
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
+// ModelVar is a field of an object, interface or input type. Tok is the
+// token of a built in scalar type, or IDENT with the type name in Lit.
+// ItemRequired marks the items of a list type as non-null. Default holds the
+// default value of an input field in schema notation, or "" if it has none.
+// Description holds the text of the description preceding the field.
 type ModelVar struct {
-	Name     string
-	Tok      Token
-	Arg      []GqlArg
-	Lit      string
-	Required bool
-	List     bool
+	Name         string
+	Description  string
+	Tok          Token
+	Arg          []GqlArg
+	Lit          string
+	Required     bool
+	List         bool
+	ItemRequired bool
+	Default      string
+	Directives   []Directive
+	Comments     Comments
+	Pos          Pos
 }
 
+// GqlArg is an argument of a field, typed like a ModelVar.
 type GqlArg struct {
-	Name     string
-	Tok      Token
-	Lit      string
-	Default  string
-	Required bool
+	Name         string
+	Description  string
+	Tok          Token
+	Lit          string
+	Default      string
+	Required     bool
+	List         bool
+	ItemRequired bool
+	Directives   []Directive
+	Comments     Comments
+	Pos          Pos
 }
 
+// Directive is a directive applied in the schema, such as
+// @deprecated(reason: "Use name").
+type Directive struct {
+	Name string
+	Args []DirectiveArg
+}
+
+// DirectiveArg is an argument of a directive. Value holds the literal as
+// written in the schema.
+type DirectiveArg struct {
+	Name  string
+	Value string
+}
+
+// Arg returns the value of the named argument and whether it was given.
+func (d Directive) Arg(name string) (string, bool) {
+	for _, arg := range d.Args {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return "", false
+}
+
+// Kind is the kind of a type definition.
+type Kind int
+
+const (
+	KindObject Kind = iota
+	KindInterface
+	KindInput
+	KindEnum
+	KindScalar
+	KindUnion
+)
+
+// String returns the keyword introducing definitions of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindObject:
+		return "type"
+	case KindInterface:
+		return "interface"
+	case KindInput:
+		return "input"
+	case KindEnum:
+		return "enum"
+	case KindScalar:
+		return "scalar"
+	case KindUnion:
+		return "union"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// EnumValue is a value of an enum type.
+type EnumValue struct {
+	Name        string
+	Description string
+	Directives  []Directive
+	Comments    Comments
+	Pos         Pos
+}
+
+// GqlModel is a type definition. Variables holds the fields of objects,
+// interfaces and input types, Interfaces the interfaces an object or
+// interface implements, Values the values of an enum and Types the members
+// of a union.
 type GqlModel struct {
-	Name      string
-	Variables []ModelVar
+	Name        string
+	Description string
+	Kind        Kind
+	Variables   []ModelVar
+	Interfaces  []string
+	Values      []EnumValue
+	Types       []string
+	Directives  []Directive
+	Comments    Comments
+	File        string // name of the schema file, if known
+	Pos         Pos
+}
+
+// Field returns the field with the given name, or nil if there is none.
+func (m *GqlModel) Field(name string) *ModelVar {
+	for i := range m.Variables {
+		if m.Variables[i].Name == name {
+			return &m.Variables[i]
+		}
+	}
+	return nil
+}
+
+// Comments holds the # comments of a definition, as written. Lead holds
+// the lines preceding it, Line the comment ending its last line and End,
+// for types with a body, the lines before the closing bracket. The Lead and
+// Line comments of a Document are those of its package clause and End those
+// following the last definition.
+type Comments struct {
+	Lead []string
+	Line string
+	End  []string
+}
+
+// Document is a parsed schema file.
+type Document struct {
+	Package  string
+	Types    []GqlModel
+	Comments Comments
+}
+
+// Type returns the definition of the named type, or nil if there is none.
+func (doc *Document) Type(name string) *GqlModel {
+	for i := range doc.Types {
+		if doc.Types[i].Name == name {
+			return &doc.Types[i]
+		}
+	}
+	return nil
 }
 
 type Parser struct {
@@ -32,8 +166,31 @@ type Parser struct {
 	buf struct {
 		tok Token  // last read token
 		lit string // last read literal
+		pos Pos    // position of the last read token
 		n   int    // buffer size (max=1)
 	}
+	comments []comment // comments read but not yet attached
+}
+
+// comment is a comment read by the parser. Trailing comments follow a token
+// on the same line.
+type comment struct {
+	text     string
+	trailing bool
+}
+
+// ParseError is a syntax error in a schema.
+type ParseError struct {
+	File string // name of the schema file, if known
+	Pos  Pos
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%s: %s", e.File, e.Pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
 }
 
 // NewParser returns a new instance of Parser.
@@ -61,70 +218,215 @@ func (p *Parser) scan() (tok Token, lit string) {
 	tok, lit = p.s.Scan()
 
 	// Save it to the buffer in case we unscan later.
-	p.buf.tok, p.buf.lit = tok, lit
+	p.buf.tok, p.buf.lit, p.buf.pos = tok, lit, p.s.Pos()
 
 	return
 }
 
+// scanIgnoreWhitespace returns the next token that is neither whitespace
+// nor a comment. Comments are kept until a definition claims them.
 func (p *Parser) scanIgnoreWhitespace() (tok Token, lit string) {
-	tok, lit = p.scan()
-	if tok == WS {
+	newline := false
+	for {
 		tok, lit = p.scan()
+		switch tok {
+		case WS:
+			newline = newline || strings.Contains(lit, "\n")
+		case COMMENT:
+			p.comments = append(p.comments, comment{strings.TrimRight(lit, " \t\r"), !newline})
+		default:
+			return
+		}
 	}
-	return
+}
+
+// leadComments returns the comments read since the last claim.
+func (p *Parser) leadComments() []string {
+	var lines []string
+	for _, c := range p.comments {
+		lines = append(lines, c.text)
+	}
+	p.comments = nil
+	return lines
+}
+
+// lineComment returns the comment following the definition just parsed on
+// its last line, if any.
+func (p *Parser) lineComment() string {
+	p.scanIgnoreWhitespace()
+	p.unscan()
+	if len(p.comments) == 0 || !p.comments[0].trailing {
+		return ""
+	}
+	text := p.comments[0].text
+	p.comments = p.comments[1:]
+	return text
 }
 
 // unscan pushes the previously read token back onto the buffer.
 func (p *Parser) unscan() { p.buf.n = 1 }
 
+// errorf returns a ParseError at the position of the last read token.
+func (p *Parser) errorf(format string, a ...interface{}) error {
+	return &ParseError{Pos: p.buf.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+// isName reports whether the token can be used as a name.
+func isName(tok Token, lit string) bool {
+	if isKeyword(tok) {
+		return true
+	}
+	return tok == IDENT && lit != "" && (isLetter(rune(lit[0])) || lit[0] == '_')
+}
+
+// gqlType is a parsed type reference such as [String!]!.
+type gqlType struct {
+	tok          Token
+	lit          string
+	list         bool
+	itemRequired bool
+	required     bool
+}
+
+// fieldType returns the type of a field.
+func fieldType(v *ModelVar) gqlType {
+	return gqlType{v.Tok, v.Lit, v.List, v.ItemRequired, v.Required}
+}
+
+// argType returns the type of an argument.
+func argType(arg *GqlArg) gqlType {
+	return gqlType{arg.Tok, arg.Lit, arg.List, arg.ItemRequired, arg.Required}
+}
+
+// name returns the name of the named type, spelling built in scalars as in
+// the GraphQL specification.
+func (t gqlType) name() string {
+	if name := scalarName(t.tok); name != "" {
+		return name
+	}
+	return t.lit
+}
+
+// String returns the schema notation for the type.
+func (t gqlType) String() string {
+	name := t.name()
+	if t.list {
+		if t.itemRequired {
+			name += "!"
+		}
+		name = "[" + name + "]"
+	}
+	if t.required {
+		name += "!"
+	}
+	return name
+}
+
+// parseType parses a type reference. The non-null marker of the whole type
+// is left to the caller, since arguments may put it after their default.
+func (p *Parser) parseType() (*gqlType, error) {
+	var typ gqlType
+	tok, lit := p.scanIgnoreWhitespace()
+	if tok == SQBRACKETOPEN {
+		typ.list = true
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == SQBRACKETOPEN {
+			return nil, p.errorf("nested lists are not supported err 25")
+		}
+	}
+	if scalarName(tok) != "" {
+		typ.tok = tok
+	} else if isName(tok, lit) {
+		typ.tok = IDENT
+	} else {
+		return nil, p.errorf("found %q, expected Type err 8", lit)
+	}
+	typ.lit = lit
+	if typ.list {
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == EXCLAMATION {
+			typ.itemRequired = true
+			tok, lit = p.scanIgnoreWhitespace()
+		}
+		if tok != SQBRACKETCLOSE {
+			return nil, p.errorf("found %q, expected ] err8", lit)
+		}
+	}
+	return &typ, nil
+}
+
 func (p *Parser) parseArg() (*GqlArg, error) {
 	var thisArg GqlArg
+	var err error
+	if thisArg.Description, err = p.parseDescription(); err != nil {
+		return nil, err
+	}
 	tok1, lit1 := p.scanIgnoreWhitespace()
-	if tok1 != IDENT {
-		return nil, fmt.Errorf("found %q, expected Identifier err 6", lit1)
+	if !isName(tok1, lit1) {
+		return nil, p.errorf("found %q, expected Identifier err 6", lit1)
 	}
 	thisArg.Name = lit1
+	thisArg.Pos = p.buf.pos
+	thisArg.Comments.Lead = p.leadComments()
 	tok1, lit1 = p.scanIgnoreWhitespace()
 	if tok1 != COLON {
-		return nil, fmt.Errorf("found %q, expected ':' err 7", lit1)
+		return nil, p.errorf("found %q, expected ':' err 7", lit1)
 	}
-	tok1, lit1 = p.scanIgnoreWhitespace()
-	if TokenCheck(tok1) == true {
-		thisArg.Tok = tok1
-		thisArg.Lit = lit1
-	} else {
-		return nil, fmt.Errorf("found %q, expected Type err 8", lit1)
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	thisArg.Tok, thisArg.Lit = typ.tok, typ.lit
+	thisArg.List, thisArg.ItemRequired = typ.list, typ.itemRequired
+
+	// The non-null marker may come before or, as in older schemas, after
+	// the default value.
+	tok1, _ = p.scanIgnoreWhitespace()
+	if tok1 == EXCLAMATION {
+		thisArg.Required = true
+		tok1, _ = p.scanIgnoreWhitespace()
 	}
-	tok1, lit1 = p.scanIgnoreWhitespace()
 	if tok1 == EQUAL {
-		tok1, lit1 = p.scanIgnoreWhitespace()
-		if tok1 != IDENT {
-			return nil, fmt.Errorf("found %s, expected Type err 9", lit1)
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
 		}
-		thisArg.Default = lit1
-
-		tok1, lit1 = p.scanIgnoreWhitespace()
+		thisArg.Default = value.String()
+		tok1, _ = p.scanIgnoreWhitespace()
 	}
-	if tok1 == EXCLAMATION {
+	if tok1 == EXCLAMATION && !thisArg.Required {
 		thisArg.Required = true
 	} else {
 		p.unscan()
 	}
-
+	if thisArg.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	// A comma ending the line precedes its comment.
+	if tok1, _ = p.scanIgnoreWhitespace(); tok1 != COMMA {
+		p.unscan()
+	}
+	thisArg.Comments.Line = p.lineComment()
 	return &thisArg, nil
 
 }
 
 func (p *Parser) parseInner() (*ModelVar, error) {
 	var curvar ModelVar
+	var err error
+	if curvar.Description, err = p.parseDescription(); err != nil {
+		return nil, err
+	}
 	tok, lit := p.scanIgnoreWhitespace()
 	if tok == EOF {
-		return nil, nil
+		return nil, p.errorf("unexpected EOF, expected } err 19")
 	}
-	if tok != IDENT {
-		return nil, fmt.Errorf("found %q, expected Identifier err 14", lit)
+	if !isName(tok, lit) {
+		return nil, p.errorf("found %q, expected Identifier err 14", lit)
 	}
 	curvar.Name = lit
+	curvar.Pos = p.buf.pos
+	curvar.Comments.Lead = p.leadComments()
 	tok, lit = p.scanIgnoreWhitespace()
 	if tok == BRACKETOPEN {
 		for {
@@ -135,68 +437,268 @@ func (p *Parser) parseInner() (*ModelVar, error) {
 			curvar.Arg = append(curvar.Arg, *curArg)
 
 			tok2, lit2 := p.scanIgnoreWhitespace()
+			if tok2 == COMMA {
+				tok2, lit2 = p.scanIgnoreWhitespace()
+			}
 			if tok2 == BRACKETCLOSE {
 				break
 			}
-			if tok2 != COMMA {
-				return nil, fmt.Errorf("found %q, expected , or ) err 15", lit2)
+			p.unscan()
+			if !isName(tok2, lit2) && !isDescription(tok2, lit2) {
+				return nil, p.errorf("found %q, expected , or ) err 15", lit2)
 			}
 		}
 		tok, lit = p.scanIgnoreWhitespace()
 	}
 
 	if tok != COLON {
-		return nil, fmt.Errorf("found %q, expected : err16", lit)
+		return nil, p.errorf("found %q, expected : err16", lit)
 	}
-	tok1, lit1 := p.scanIgnoreWhitespace()
-	if tok1 == SQBRACKETOPEN {
-		tok, lit = p.scanIgnoreWhitespace()
-		if tok != IDENT {
-			return nil, fmt.Errorf("found %q, expected Identifier err17", lit)
-		}
-		curvar.Tok = tok
-		curvar.Lit = lit
-		tok, lit = p.scanIgnoreWhitespace()
-		if tok != SQBRACKETCLOSE {
-			return nil, fmt.Errorf("found %q, expected ] err8", lit)
-		}
-		curvar.List = true
-	} else if TokenCheck(tok1) == true {
-		curvar.Tok = tok1
-		curvar.Lit = lit1
-	} else {
-		return nil, fmt.Errorf("found %q, expected member variable declaration err13", lit1)
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
 	}
-	tok1, lit1 = p.scanIgnoreWhitespace()
+	curvar.Tok, curvar.Lit = typ.tok, typ.lit
+	curvar.List, curvar.ItemRequired = typ.list, typ.itemRequired
+	tok1, _ := p.scanIgnoreWhitespace()
 	if tok1 == EXCLAMATION {
 		curvar.Required = true
 	} else {
 		p.unscan()
 	}
+	if tok1, _ = p.scanIgnoreWhitespace(); tok1 == EQUAL {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		curvar.Default = value.String()
+	} else {
+		p.unscan()
+	}
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	curvar.Directives = directives
+	if tok1, _ = p.scanIgnoreWhitespace(); tok1 != COMMA {
+		p.unscan()
+	}
+	curvar.Comments.Line = p.lineComment()
 	return &curvar, nil
 }
 
+// isDescription reports whether a token is a string, which describes the
+// definition that follows it.
+func isDescription(tok Token, lit string) bool {
+	return tok == IDENT && strings.HasPrefix(lit, `"`)
+}
+
+// parseDescription parses an optional description and returns its text.
+func (p *Parser) parseDescription() (string, error) {
+	tok, lit := p.scanIgnoreWhitespace()
+	if !isDescription(tok, lit) {
+		p.unscan()
+		return "", nil
+	}
+	text, err := unquote(lit)
+	if err != nil {
+		return "", p.errorf("found %s, expected description err 31", lit)
+	}
+	return text, nil
+}
+
+// parseDirectives parses any directives applied at the current position.
+func (p *Parser) parseDirectives() ([]Directive, error) {
+	var directives []Directive
+	for {
+		if tok, _ := p.scanIgnoreWhitespace(); tok != AT {
+			p.unscan()
+			return directives, nil
+		}
+		var directive Directive
+		tok, lit := p.scan()
+		if !isName(tok, lit) {
+			return nil, p.errorf("found %q, expected directive name err 20", lit)
+		}
+		directive.Name = lit
+		if tok, _ = p.scanIgnoreWhitespace(); tok != BRACKETOPEN {
+			p.unscan()
+			directives = append(directives, directive)
+			continue
+		}
+		for {
+			var arg DirectiveArg
+			if tok, lit = p.scanIgnoreWhitespace(); !isName(tok, lit) {
+				return nil, p.errorf("found %q, expected Identifier err 21", lit)
+			}
+			arg.Name = lit
+			if tok, lit = p.scanIgnoreWhitespace(); tok != COLON {
+				return nil, p.errorf("found %q, expected ':' err 22", lit)
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			arg.Value = value.String()
+			directive.Args = append(directive.Args, arg)
+
+			tok, lit = p.scanIgnoreWhitespace()
+			if tok == COMMA {
+				tok, lit = p.scanIgnoreWhitespace()
+			}
+			if tok == BRACKETCLOSE {
+				break
+			}
+			p.unscan()
+		}
+		directives = append(directives, directive)
+	}
+}
+
+// parseNames parses a list of type names separated by sep, which may also
+// precede the first name. Objects separate their interfaces with &, or
+// commas in older schemas, and unions separate their members with |.
+func (p *Parser) parseNames(sep Token) ([]string, error) {
+	var names []string
+	if tok, _ := p.scanIgnoreWhitespace(); tok != sep {
+		p.unscan()
+	}
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if !isName(tok, lit) {
+			return nil, p.errorf("found %q, expected Identifier err 26", lit)
+		}
+		names = append(names, lit)
+		tok, _ = p.scanIgnoreWhitespace()
+		if tok != sep && !(sep == AMPERSAND && tok == COMMA) {
+			p.unscan()
+			return names, nil
+		}
+	}
+}
+
+// parseEnumValues parses the body of an enum up to the closing bracket.
+func (p *Parser) parseEnumValues() ([]EnumValue, error) {
+	var values []EnumValue
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == CURLBRACKETCLOSE {
+			return values, nil
+		}
+		if tok == EOF {
+			return nil, p.errorf("unexpected EOF, expected } err 19")
+		}
+		var value EnumValue
+		if isDescription(tok, lit) {
+			p.unscan()
+			description, err := p.parseDescription()
+			if err != nil {
+				return nil, err
+			}
+			value.Description = description
+			tok, lit = p.scanIgnoreWhitespace()
+		}
+		if !isName(tok, lit) {
+			return nil, p.errorf("found %q, expected enum value err 27", lit)
+		}
+		value.Name, value.Pos = lit, p.buf.pos
+		value.Comments.Lead = p.leadComments()
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		value.Directives = directives
+		if tok, _ = p.scanIgnoreWhitespace(); tok != COMMA {
+			p.unscan()
+		}
+		value.Comments.Line = p.lineComment()
+		values = append(values, value)
+	}
+}
+
+// Parse parses the next type definition. It returns io.EOF once the input
+// is exhausted; any other error means the schema is malformed.
 func (p *Parser) Parse() (*GqlModel, error) {
 	gqlmodel := &GqlModel{}
 
 	tok, lit := p.scanIgnoreWhitespace()
-	if tok != TYPE {
-		if lit == "" {
-			return nil, fmt.Errorf("EOF reached")
-		} else {
-			return nil, fmt.Errorf("found %q, expected Type or schema, err1", lit)
+	if tok == EOF {
+		return nil, io.EOF
+	}
+	if isDescription(tok, lit) {
+		p.unscan()
+		description, err := p.parseDescription()
+		if err != nil {
+			return nil, err
 		}
+		gqlmodel.Description = description
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+	gqlmodel.Pos = p.buf.pos
+	switch tok {
+	case TYPE:
+		gqlmodel.Kind = KindObject
+	case INTERFACE:
+		gqlmodel.Kind = KindInterface
+	case INPUT:
+		gqlmodel.Kind = KindInput
+	case ENUM:
+		gqlmodel.Kind = KindEnum
+	case SCALAR:
+		gqlmodel.Kind = KindScalar
+	case UNION:
+		gqlmodel.Kind = KindUnion
+	default:
+		return nil, p.errorf("found %q, expected Type or schema, err1", lit)
 	}
 
 	tok, lit = p.scanIgnoreWhitespace()
-	if tok != IDENT {
-		return nil, fmt.Errorf("found %q, expected Identifier, err2", lit)
+	if !isName(tok, lit) {
+		return nil, p.errorf("found %q, expected Identifier, err2", lit)
 	} else {
 		gqlmodel.Name = lit
 	}
+	gqlmodel.Comments.Lead = p.leadComments()
+
+	var err error
+	if gqlmodel.Kind == KindObject || gqlmodel.Kind == KindInterface {
+		if tok, _ = p.scanIgnoreWhitespace(); tok == IMPLEMENTS {
+			if gqlmodel.Interfaces, err = p.parseNames(AMPERSAND); err != nil {
+				return nil, err
+			}
+		} else {
+			p.unscan()
+		}
+	}
+	if gqlmodel.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	switch gqlmodel.Kind {
+	case KindScalar:
+		gqlmodel.Comments.Line = p.lineComment()
+		return gqlmodel, nil
+	case KindUnion:
+		if tok, lit = p.scanIgnoreWhitespace(); tok != EQUAL {
+			return nil, p.errorf("found %q, expected = err 28", lit)
+		}
+		if gqlmodel.Types, err = p.parseNames(PIPE); err != nil {
+			return nil, err
+		}
+		gqlmodel.Comments.Line = p.lineComment()
+		return gqlmodel, nil
+	}
 
 	if tok, lit = p.scanIgnoreWhitespace(); tok != CURLBRACKETOPEN {
-		return nil, fmt.Errorf("found %q, expected open bracket err3", lit)
+		return nil, p.errorf("found %q, expected open bracket err3", lit)
+	}
+	if gqlmodel.Kind == KindEnum {
+		if gqlmodel.Values, err = p.parseEnumValues(); err != nil {
+			return nil, err
+		}
+		gqlmodel.Comments.End = p.leadComments()
+		gqlmodel.Comments.Line = p.lineComment()
+		return gqlmodel, nil
 	}
 
 	for {
@@ -209,21 +711,82 @@ func (p *Parser) Parse() (*GqlModel, error) {
 		if err != nil {
 			return nil, err
 		}
-		if mdlvar == nil {
-			break
-		}
 		gqlmodel.Variables = append(gqlmodel.Variables, *mdlvar)
 	}
+	gqlmodel.Comments.End = p.leadComments()
+	gqlmodel.Comments.Line = p.lineComment()
 	return gqlmodel, nil
 }
 func (p *Parser) ParsePackage() (string, error) {
 	tok, lit := p.scanIgnoreWhitespace()
 	if tok != PACKAGE {
-		return "", fmt.Errorf("found %q, expected package err 18", lit)
+		return "", p.errorf("found %q, expected package err 18", lit)
 	}
 	tok, lit = p.scanIgnoreWhitespace()
 	if lit == "" {
-		return "", fmt.Errorf("missing package name")
+		return "", p.errorf("missing package name")
 	}
 	return lit, nil
 }
+
+// ParseDocument parses an optional package clause followed by every type
+// definition up to the end of the input.
+func (p *Parser) ParseDocument() (*Document, error) {
+	doc := &Document{}
+	tok, _ := p.scanIgnoreWhitespace()
+	p.unscan()
+	if tok == PACKAGE {
+		packageName, err := p.ParsePackage()
+		if err != nil {
+			return nil, err
+		}
+		doc.Package = packageName
+		doc.Comments.Lead = p.leadComments()
+		doc.Comments.Line = p.lineComment()
+	}
+	for {
+		obj, err := p.Parse()
+		if err == io.EOF {
+			doc.Comments.End = p.leadComments()
+			return doc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		doc.Types = append(doc.Types, *obj)
+	}
+}
+
+// ParseFile parses the schema read from src. Syntax errors are reported as a
+// *ParseError naming the file.
+func ParseFile(name string, src io.Reader) (*Document, error) {
+	doc, err := NewParser(src).ParseDocument()
+	if perr, ok := err.(*ParseError); ok {
+		perr.File = name
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range doc.Types {
+		doc.Types[i].File = name
+	}
+	return doc, nil
+}
+
+// Merge adds the types of other to doc, with the comments following them.
+// Both documents must declare the same package, if any; the comments of the
+// first package clause are kept.
+func (doc *Document) Merge(other *Document) error {
+	if other.Package != "" {
+		if doc.Package != "" && doc.Package != other.Package {
+			return fmt.Errorf("package %s conflicts with package %s", other.Package, doc.Package)
+		}
+		doc.Package = other.Package
+	}
+	doc.Types = append(doc.Types, other.Types...)
+	if len(doc.Comments.Lead) == 0 && doc.Comments.Line == "" {
+		doc.Comments.Lead, doc.Comments.Line = other.Comments.Lead, other.Comments.Line
+	}
+	doc.Comments.End = append(doc.Comments.End, other.Comments.End...)
+	return nil
+}
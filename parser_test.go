@@ -2,6 +2,7 @@ package graphqlgenerator
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"testing"
@@ -19,37 +20,37 @@ type Mutation {
 	p := NewParser(reader)
 	obj, err := p.Parse()
 	if err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 	if obj.Name != "Query" {
 		t.Errorf("Parse failed, found %s as name instead of Query", obj.Name)
 	}
 	firstVar := obj.Variables[0]
 	if err = testModelVarNoArg(firstVar, "timeseries", INT, "int", false, false); err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 	secondVar := obj.Variables[1]
 	if err = testModelVarNoArg(secondVar, "transactions", IDENT, "Transactions", true, false); err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 	obj, err = p.Parse() // to test that successive calls of Parse works
 	if err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 	if obj.Name != "Mutation" {
 		t.Errorf("Parse failed, found %s as name instead of Mutation", obj.Name)
 	}
 	firstVar = obj.Variables[0]
 	if err = testModelVarNoArg(firstVar, "performance", IDENT, "PerformanceSummary", true, true); err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 
 	if err = testGqlArg(firstVar.Arg[0], "word", INT, "int", `"100"`, true); err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 
 	if err = testGqlArg(firstVar.Arg[1], "fish", IDENT, "Animal", "", false); err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 
 }
@@ -96,3 +97,87 @@ func testGqlArg(obj GqlArg, name string, tok Token, lit string, defaultstring st
 	}
 	return nil
 }
+
+func Test_ParseEOF(t *testing.T) {
+	reader := strings.NewReader("type Query {\n  timeseries: int\n}\n")
+	p := NewParser(reader)
+	if _, err := p.Parse(); err != nil {
+		t.Error(err)
+	}
+	if _, err := p.Parse(); err != io.EOF {
+		t.Errorf("Parse did not return io.EOF at end of input, returned %v", err)
+	}
+
+	reader = strings.NewReader("type Query {\n  timeseries: int\n")
+	p = NewParser(reader)
+	if _, err := p.Parse(); err == nil || err == io.EOF {
+		t.Errorf("Parse did not report an unterminated type, returned %v", err)
+	}
+}
+
+func Test_ParseErrorPosition(t *testing.T) {
+	reader := strings.NewReader("type Query {\n  timeseries: int\n  name String\n}\n")
+	_, err := ParseFile("schema.graphql", reader)
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("ParseFile did not return a *ParseError, returned %v", err)
+	}
+	if perr.Pos != (Pos{Line: 3, Column: 8}) {
+		t.Errorf("ParseError reported position %s instead of 3:8", perr.Pos)
+	}
+	if !strings.HasPrefix(perr.Error(), "schema.graphql:3:8: ") {
+		t.Errorf("ParseError message %q does not start with the file and position", perr.Error())
+	}
+}
+
+func Test_ParseKinds(t *testing.T) {
+	testString := `interface Node { id: ID! }
+type User implements Node & Named @key(fields: "id") {
+  id: ID!
+  type: String
+  tags: [String!]!
+}
+input Filter {
+  first: Int! = 10,
+  query: String
+}
+enum Role { ADMIN, USER @deprecated }
+scalar Date
+union Result = | User | Node`
+	doc, err := NewParser(strings.NewReader(testString)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []Kind{KindInterface, KindObject, KindInput, KindEnum, KindScalar, KindUnion}
+	if len(doc.Types) != len(kinds) {
+		t.Fatalf("ParseDocument found %d types instead of %d", len(doc.Types), len(kinds))
+	}
+	for i, kind := range kinds {
+		if doc.Types[i].Kind != kind {
+			t.Errorf("ParseDocument found %s %s instead of %s", doc.Types[i].Kind, doc.Types[i].Name, kind)
+		}
+	}
+	user := doc.Type("User")
+	if strings.Join(user.Interfaces, ",") != "Node,Named" {
+		t.Errorf("Parse found interfaces %v instead of [Node Named]", user.Interfaces)
+	}
+	if len(user.Directives) != 1 || user.Directives[0].Name != "key" {
+		t.Errorf("Parse found directives %v instead of @key", user.Directives)
+	}
+	if err = testModelVarNoArg(user.Variables[1], "type", STRING, "String", false, false); err != nil {
+		t.Error(err)
+	}
+	if tags := user.Variables[2]; !tags.List || !tags.ItemRequired || !tags.Required {
+		t.Errorf("Parse did not read [String!]! for tags, found %+v", tags)
+	}
+	if first := doc.Type("Filter").Field("first"); first == nil || first.Default != "10" || !first.Required {
+		t.Errorf("Parse did not read the default of Filter.first, found %+v", first)
+	}
+	role := doc.Type("Role")
+	if len(role.Values) != 2 || role.Values[1].Name != "USER" || len(role.Values[1].Directives) != 1 {
+		t.Errorf("Parse found enum values %+v instead of ADMIN, USER @deprecated", role.Values)
+	}
+	if types := doc.Type("Result").Types; strings.Join(types, ",") != "User,Node" {
+		t.Errorf("Parse found union members %v instead of [User Node]", types)
+	}
+}
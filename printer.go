@@ -0,0 +1,178 @@
+package graphqlgenerator
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Fprint writes doc to w as a schema in canonical layout: one definition per
+// block separated by blank lines, fields indented by two spaces and built in
+// scalars spelled as in the GraphQL specification. Descriptions precede what
+// they describe, as block strings when they span lines, and comments are
+// kept with the definitions they were attached to. Arguments are listed
+// inline unless one of them has a description or comment. Parsing the
+// output yields doc again, and printing that yields the same text.
+func Fprint(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	if doc.Package != "" {
+		writeComments(bw, doc.Comments.Lead, "")
+		bw.WriteString("package " + doc.Package + lineComment(doc.Comments.Line) + "\n")
+	}
+	for i, obj := range doc.Types {
+		if i > 0 || doc.Package != "" {
+			bw.WriteString("\n")
+		}
+		writeComments(bw, obj.Comments.Lead, "")
+		writeDescription(bw, obj.Description, "")
+		bw.WriteString(obj.Kind.String() + " " + obj.Name)
+		if len(obj.Interfaces) > 0 {
+			bw.WriteString(" implements " + strings.Join(obj.Interfaces, " & "))
+		}
+		bw.WriteString(directivesString(obj.Directives))
+		switch obj.Kind {
+		case KindScalar:
+			bw.WriteString(lineComment(obj.Comments.Line) + "\n")
+			continue
+		case KindUnion:
+			bw.WriteString(" = " + strings.Join(obj.Types, " | ") + lineComment(obj.Comments.Line) + "\n")
+			continue
+		}
+		bw.WriteString(" {\n")
+		for _, value := range obj.Values {
+			writeComments(bw, value.Comments.Lead, "  ")
+			writeDescription(bw, value.Description, "  ")
+			bw.WriteString("  " + value.Name + directivesString(value.Directives) + lineComment(value.Comments.Line) + "\n")
+		}
+		for _, element := range obj.Variables {
+			writeComments(bw, element.Comments.Lead, "  ")
+			writeDescription(bw, element.Description, "  ")
+			bw.WriteString("  " + element.Name)
+			if len(element.Arg) > 0 {
+				writeArgs(bw, element.Arg)
+			}
+			bw.WriteString(": " + fieldType(&element).String())
+			if element.Default != "" {
+				bw.WriteString(" = " + element.Default)
+			}
+			bw.WriteString(directivesString(element.Directives) + lineComment(element.Comments.Line) + "\n")
+		}
+		writeComments(bw, obj.Comments.End, "  ")
+		bw.WriteString("}" + lineComment(obj.Comments.Line) + "\n")
+	}
+	if len(doc.Comments.End) > 0 {
+		if len(doc.Types) > 0 || doc.Package != "" {
+			bw.WriteString("\n")
+		}
+		writeComments(bw, doc.Comments.End, "")
+	}
+	return bw.Flush()
+}
+
+// Format returns src, the content of the schema file name, in the canonical
+// layout of Fprint. Comments and descriptions are kept; syntax errors are
+// reported as a *ParseError naming the file.
+func Format(name string, src []byte) ([]byte, error) {
+	doc, err := ParseFile(name, bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := Fprint(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeArgs writes the parenthesized arguments of a field, one per line if
+// any of them has a description or comment.
+func writeArgs(bw *bufio.Writer, args []GqlArg) {
+	multiline := false
+	for _, arg := range args {
+		if arg.Description != "" || len(arg.Comments.Lead) > 0 || arg.Comments.Line != "" {
+			multiline = true
+		}
+	}
+	bw.WriteString("(")
+	for i, arg := range args {
+		switch {
+		case multiline:
+			bw.WriteString("\n")
+			writeComments(bw, arg.Comments.Lead, "    ")
+			writeDescription(bw, arg.Description, "    ")
+			bw.WriteString("    ")
+		case i > 0:
+			bw.WriteString(", ")
+		}
+		bw.WriteString(arg.Name + ": " + argType(&arg).String())
+		if arg.Default != "" {
+			bw.WriteString(" = " + arg.Default)
+		}
+		bw.WriteString(directivesString(arg.Directives) + lineComment(arg.Comments.Line))
+	}
+	if multiline {
+		bw.WriteString("\n  ")
+	}
+	bw.WriteString(")")
+}
+
+// writeComments writes comment lines indented by indent.
+func writeComments(bw *bufio.Writer, lines []string, indent string) {
+	for _, line := range lines {
+		bw.WriteString(indent + line + "\n")
+	}
+}
+
+// lineComment returns the notation for a comment ending a line, or "" if
+// there is none.
+func lineComment(text string) string {
+	if text == "" {
+		return ""
+	}
+	return " " + text
+}
+
+// writeDescription writes a description, if any, on the lines before a
+// definition indented by indent.
+func writeDescription(bw *bufio.Writer, text, indent string) {
+	if text == "" {
+		return
+	}
+	var block strings.Builder
+	block.WriteString("\n")
+	for _, line := range strings.Split(strings.ReplaceAll(text, `"""`, `\"""`), "\n") {
+		if line != "" {
+			block.WriteString(indent + line)
+		}
+		block.WriteString("\n")
+	}
+	block.WriteString(indent)
+	// Block strings lose surrounding blank lines and carriage returns, so
+	// such text is quoted instead.
+	if !strings.Contains(text, "\n") || blockString(block.String()) != text {
+		bw.WriteString(indent + quote(text) + "\n")
+		return
+	}
+	bw.WriteString(indent + `"""` + block.String() + `"""` + "\n")
+}
+
+// directivesString returns the schema notation for directives, each preceded
+// by a space.
+func directivesString(directives []Directive) string {
+	text := ""
+	for _, directive := range directives {
+		text += " @" + directive.Name
+		if len(directive.Args) > 0 {
+			text += "("
+			for i, arg := range directive.Args {
+				if i > 0 {
+					text += ", "
+				}
+				text += arg.Name + ": " + arg.Value
+			}
+			text += ")"
+		}
+	}
+	return text
+}
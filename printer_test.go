@@ -0,0 +1,279 @@
+package graphqlgenerator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Fprint(t *testing.T) {
+	testString := `package models
+type Query {
+  timeseries: int
+  transactions: Transactions! 
+}
+type Mutation {
+  performance(word: int = "100"!, fish: Animal): [PerformanceSummary]! 
+}`
+	want := `package models
+
+type Query {
+  timeseries: Int
+  transactions: Transactions!
+}
+
+type Mutation {
+  performance(word: Int! = "100", fish: Animal): [PerformanceSummary]!
+}
+`
+	doc, err := NewParser(strings.NewReader(testString)).ParseDocument()
+	if err != nil {
+		t.Error(err)
+	}
+	var b strings.Builder
+	if err = Fprint(&b, doc); err != nil {
+		t.Error(err)
+	}
+	if b.String() != want {
+		t.Errorf("Fprint returned\n%s\ninstead of\n%s", b.String(), want)
+	}
+
+	doc, err = NewParser(strings.NewReader(b.String())).ParseDocument()
+	if err != nil {
+		t.Error(err)
+	}
+	var again strings.Builder
+	if err = Fprint(&again, doc); err != nil {
+		t.Error(err)
+	}
+	if again.String() != want {
+		t.Errorf("Fprint is not stable, returned\n%s\ninstead of\n%s", again.String(), want)
+	}
+}
+
+func Test_FprintKinds(t *testing.T) {
+	want := `interface Node {
+  key: String!
+}
+
+type User implements Node & Named @key(fields: "key") {
+  key: String!
+  tags(first: Int = 10): [String!]!
+}
+
+input Filter {
+  first: Int! = 10
+}
+
+enum Role {
+  ADMIN
+  USER @deprecated
+}
+
+scalar Date
+
+union Result = User | Node
+`
+	doc, err := NewParser(strings.NewReader(want)).ParseDocument()
+	if err != nil {
+		t.Error(err)
+	}
+	var b strings.Builder
+	if err = Fprint(&b, doc); err != nil {
+		t.Error(err)
+	}
+	if b.String() != want {
+		t.Errorf("Fprint returned\n%s\ninstead of\n%s", b.String(), want)
+	}
+}
+
+func Test_FprintDescriptions(t *testing.T) {
+	want := `"The root query."
+type Query {
+  "Finds users."
+  users(
+    "Maximum number of users."
+    first: Int = 10
+    role: Role = ADMIN
+  ): [User!]! @deprecated(reason: "Use search.")
+}
+
+"""
+A user of the service.
+
+Users are identified by "key".
+"""
+type User {
+  key: String!
+  """
+  The name, as given
+    by the user.
+  """
+  name: String
+}
+
+enum Role {
+  "Everything, \"really\"."
+  ADMIN
+  USER
+}
+`
+	doc, err := NewParser(strings.NewReader(want)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Types[1].Description; got != "A user of the service.\n\nUsers are identified by \"key\"." {
+		t.Errorf("parsed description %q", got)
+	}
+	if got := doc.Types[0].Variables[0].Arg[0].Description; got != "Maximum number of users." {
+		t.Errorf("parsed argument description %q", got)
+	}
+	var b strings.Builder
+	if err = Fprint(&b, doc); err != nil {
+		t.Error(err)
+	}
+	if b.String() != want {
+		t.Errorf("Fprint returned\n%s\ninstead of\n%s", b.String(), want)
+	}
+}
+
+// Test_FprintRoundTrip checks that printing a parsed schema and parsing the
+// output again yields the same document, and that printing is idempotent.
+func Test_FprintRoundTrip(t *testing.T) {
+	schemas := []string{
+		`type Query { a: Int, b(x: [Int!]! = [1, 2], y: String = "a\tb"): [String]! }`,
+		`"""
+    Indented
+      block
+  """ type T implements A & B @key(fields: "id") { "d" id: ID! @external }
+interface A { "\"\"\"" id: ID! }
+interface B { id: ID! }
+input I { """one""" n: Int! = 3, o: I = {n: 1}, s: String = """x"y""" }
+"e" enum E { "v" V @deprecated(reason: "no") W }
+"s" scalar S @specifiedBy(url: "https://example.com")
+"u" union U = T | Q
+type Q { f("a" a: E = V, "b" b: I): S }`,
+		`type Q { f(a: String = "line\nbreak"): String }
+"\n lead" type R { "trail\n" g: Int }`,
+		`# head
+package p # p
+type Q { # q
+  f(a: Int # a
+    "b" b: Int): Int # f
+  # end
+} # q
+enum E { A, # a
+  B } # e
+# tail`,
+	}
+	for _, schema := range schemas {
+		doc, err := NewParser(strings.NewReader(schema)).ParseDocument()
+		if err != nil {
+			t.Errorf("%v parsing\n%s", err, schema)
+			continue
+		}
+		var first strings.Builder
+		if err = Fprint(&first, doc); err != nil {
+			t.Error(err)
+		}
+		again, err := NewParser(strings.NewReader(first.String())).ParseDocument()
+		if err != nil {
+			t.Errorf("%v parsing printed schema\n%s", err, first.String())
+			continue
+		}
+		if !reflect.DeepEqual(clearPositions(doc), clearPositions(again)) {
+			t.Errorf("parsing the printed schema changed it\n%s", first.String())
+		}
+		var second strings.Builder
+		if err = Fprint(&second, again); err != nil {
+			t.Error(err)
+		}
+		if second.String() != first.String() {
+			t.Errorf("Fprint is not idempotent, returned\n%s\nthen\n%s", first.String(), second.String())
+		}
+	}
+}
+
+// clearPositions returns doc without the positions and file names the
+// parser records, which differ between a schema and its printed form.
+func clearPositions(doc *Document) *Document {
+	for i := range doc.Types {
+		obj := &doc.Types[i]
+		obj.Pos, obj.File = Pos{}, ""
+		for j := range obj.Variables {
+			obj.Variables[j].Pos = Pos{}
+			for k := range obj.Variables[j].Arg {
+				obj.Variables[j].Arg[k].Pos = Pos{}
+			}
+		}
+		for j := range obj.Values {
+			obj.Values[j].Pos = Pos{}
+		}
+	}
+	return doc
+}
+
+func Test_FprintComments(t *testing.T) {
+	src := `# Schema of the service.
+package models # the package
+
+# Queries.
+"root"
+type Query { # opens
+  # the users
+  users(
+    # how many
+    first: Int = 10, # at most
+    after: String
+  ): [User!]! # list
+  # nothing after
+} # closes
+
+enum Role { ADMIN # all
+  # plain
+  USER
+}
+scalar Date # a date
+# the end
+`
+	want := `# Schema of the service.
+package models # the package
+
+# Queries.
+"root"
+type Query {
+  # opens
+  # the users
+  users(
+    # how many
+    first: Int = 10 # at most
+    after: String
+  ): [User!]! # list
+  # nothing after
+} # closes
+
+enum Role {
+  ADMIN # all
+  # plain
+  USER
+}
+
+scalar Date # a date
+
+# the end
+`
+	got, err := Format("schema.graphql", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("Format returned\n%s\ninstead of\n%s", got, want)
+	}
+	if again, err := Format("schema.graphql", got); err != nil || string(again) != want {
+		t.Errorf("Format is not stable, returned\n%s\n%v", again, err)
+	}
+	d, err := FormatDiff("schema.graphql", []byte(want))
+	if err != nil || d != "" {
+		t.Errorf("FormatDiff returned %q, %v for a formatted schema", d, err)
+	}
+}
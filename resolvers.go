@@ -0,0 +1,380 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rootTypes are the names of the operation types, whose fields are always
+// resolved by the resolvers.
+var rootTypes = map[string]bool{"Query": true, "Mutation": true, "Subscription": true}
+
+// hasResolver reports whether field of obj is resolved by a resolver method
+// rather than read from the model: every field of an operation type, and
+// fields taking arguments.
+func (g *Generator) hasResolver(obj *GqlModel, field *ModelVar) bool {
+	return g.resolvers && obj.Kind == KindObject && (rootTypes[obj.Name] || len(field.Arg) > 0)
+}
+
+// resolvedFields returns the fields of obj that have resolvers.
+func (g *Generator) resolvedFields(obj *GqlModel) []ModelVar {
+	var fields []ModelVar
+	for _, field := range obj.Variables {
+		if g.hasResolver(obj, &field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// resolverName returns the name of the resolver interface of obj.
+func resolverName(obj *GqlModel) string {
+	return modelName(obj.Name) + "Resolver"
+}
+
+// argsName returns the name of the struct holding the arguments of field.
+func argsName(obj *GqlModel, field *ModelVar) string {
+	return modelName(obj.Name) + camelName(field.Name, false) + "Args"
+}
+
+// resolveFunc returns the name of the function resolving field.
+func resolveFunc(obj *GqlModel, field *ModelVar) string {
+	return "resolve" + modelName(obj.Name) + camelName(field.Name, false)
+}
+
+// resolverMethod is the method of a resolver interface resolving a field.
+type resolverMethod struct {
+	name   string
+	params []resolverParam
+	result string
+}
+
+// resolverParam is a parameter of a resolver method.
+type resolverParam struct {
+	name string
+	typ  string
+}
+
+// resolverMethod returns the method resolving field of obj.
+func (g *Generator) resolverMethod(obj *GqlModel, field *ModelVar) (*resolverMethod, error) {
+	result, err := g.modelType("", fieldType(field))
+	if err != nil {
+		return nil, err
+	}
+	m := &resolverMethod{name: camelName(field.Name, false), result: exprString(result)}
+	m.params = append(m.params, resolverParam{"ctx", "context.Context"})
+	if !rootTypes[obj.Name] {
+		m.params = append(m.params, resolverParam{"obj", "*" + modelName(obj.Name)})
+	}
+	if len(field.Arg) > 0 {
+		m.params = append(m.params, resolverParam{"args", argsName(obj, field)})
+	}
+	return m, nil
+}
+
+// signature returns the parameters and results of the method in Go
+// notation, with the parameter names if names is set.
+func (m *resolverMethod) signature(names bool) string {
+	params := make([]string, len(m.params))
+	for i, param := range m.params {
+		params[i] = param.typ
+		if names {
+			params[i] = param.name + " " + param.typ
+		}
+	}
+	return "(" + strings.Join(params, ", ") + ") (" + m.result + ", error)"
+}
+
+// resolverNames returns the Go names declared for the resolvers of obj,
+// with their owners described for error messages.
+func (g *Generator) resolverNames(obj *GqlModel) (names []string, owners []string) {
+	fields := g.resolvedFields(obj)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	names = append(names, resolverName(obj))
+	owners = append(owners, "the resolver of type "+obj.Name)
+	if !rootTypes[obj.Name] {
+		names = append(names, "source"+modelName(obj.Name))
+		owners = append(owners, "the source of type "+obj.Name)
+	}
+	for _, field := range fields {
+		names = append(names, resolveFunc(obj, &field))
+		owners = append(owners, fmt.Sprintf("the resolver of %s.%s", obj.Name, field.Name))
+		if len(field.Arg) > 0 {
+			names = append(names, argsName(obj, &field), "decode"+argsName(obj, &field))
+			owners = append(owners, fmt.Sprintf("the arguments of %s.%s", obj.Name, field.Name),
+				fmt.Sprintf("the argument decoder of %s.%s", obj.Name, field.Name))
+		}
+	}
+	return names, owners
+}
+
+// decodedTypes returns the names of the types whose values arguments of
+// resolved fields of doc are decoded from, directly or as fields of input
+// objects, that have a decoder: scalars with a model type, enums and input
+// objects.
+func (g *Generator) decodedTypes(doc *Document) []string {
+	var names []string
+	seen := make(map[string]bool)
+	var use func(typ gqlType)
+	use = func(typ gqlType) {
+		name := typ.name()
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		if _, ok := g.modelTypes[name]; ok {
+			names = append(names, name)
+			return
+		}
+		def := doc.Type(name)
+		if def == nil || (def.Kind != KindEnum && def.Kind != KindInput) {
+			return
+		}
+		names = append(names, name)
+		for _, field := range def.Variables {
+			use(fieldType(&field))
+		}
+	}
+	for i := range doc.Types {
+		obj := &doc.Types[i]
+		for _, field := range g.resolvedFields(obj) {
+			for _, arg := range field.Arg {
+				use(argType(&arg))
+			}
+		}
+	}
+	return names
+}
+
+// resolverSource returns the resolver interfaces of the document being
+// generated and the functions calling them from the generated fields.
+func (g *Generator) resolverSource() ([]byte, error) {
+	var buf bytes.Buffer
+	var objs []*GqlModel
+	for i := range g.doc.Types {
+		obj := &g.doc.Types[i]
+		if len(g.resolvedFields(obj)) > 0 {
+			objs = append(objs, obj)
+		}
+	}
+	if len(objs) == 0 {
+		return nil, nil
+	}
+
+	buf.WriteString("// Resolver gives the resolvers of the fields that are not read from the\n")
+	buf.WriteString("// models: the fields of the operation types and fields with arguments.\n")
+	buf.WriteString("type Resolver interface {\n")
+	for _, obj := range objs {
+		fmt.Fprintf(&buf, "%s() %s\n", modelName(obj.Name), resolverName(obj))
+	}
+	buf.WriteString("}\n\n")
+	buf.WriteString("var resolver Resolver\n\n")
+	buf.WriteString("// SetResolver sets the resolver called by the generated fields. It must be\n")
+	buf.WriteString("// called before the schema executes any request.\n")
+	buf.WriteString("func SetResolver(r Resolver) {\nresolver = r\n}\n\n")
+
+	for _, obj := range objs {
+		name := modelName(obj.Name)
+		root := rootTypes[obj.Name]
+		fields := g.resolvedFields(obj)
+
+		fmt.Fprintf(&buf, "// %s resolves fields of type %s.\n", resolverName(obj), obj.Name)
+		fmt.Fprintf(&buf, "type %s interface {\n", resolverName(obj))
+		for _, field := range fields {
+			method, err := g.resolverMethod(obj, &field)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "%s%s\n", method.name, method.signature(true))
+		}
+		buf.WriteString("}\n\n")
+
+		for _, field := range fields {
+			if len(field.Arg) > 0 {
+				if err := g.writeArgs(&buf, obj, &field); err != nil {
+					return nil, err
+				}
+			}
+			fmt.Fprintf(&buf, "func %s(p graphql.ResolveParams) (interface{}, error) {\n", resolveFunc(obj, &field))
+			call := fmt.Sprintf("resolver.%s().%s(p.Context", name, camelName(field.Name, false))
+			if !root {
+				fmt.Fprintf(&buf, "obj, err := source%s(p.Source)\nif err != nil {\nreturn nil, err\n}\n", name)
+				call += ", obj"
+			}
+			if len(field.Arg) > 0 {
+				fmt.Fprintf(&buf, "args, err := decode%s(p.Args)\n", argsName(obj, &field))
+				fmt.Fprintf(&buf, "if err != nil {\nreturn nil, fmt.Errorf(\"%s.%s: %%v\", err)\n}\n", obj.Name, field.Name)
+				call += ", args"
+			}
+			fmt.Fprintf(&buf, "return %s)\n}\n\n", call)
+		}
+
+		if !root {
+			fmt.Fprintf(&buf, "func source%s(source interface{}) (*%s, error) {\n", name, name)
+			buf.WriteString("switch source := source.(type) {\n")
+			fmt.Fprintf(&buf, "case *%s:\nreturn source, nil\n", name)
+			fmt.Fprintf(&buf, "case %s:\nreturn &source, nil\n", name)
+			buf.WriteString("}\n")
+			fmt.Fprintf(&buf, "return nil, fmt.Errorf(\"resolving %s: unexpected source %%T\", source)\n}\n\n", obj.Name)
+		}
+	}
+
+	for _, name := range g.decodedTypes(g.doc) {
+		if err := g.writeDecoder(&buf, name); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeArgs writes the struct holding the arguments of field and the
+// function decoding it from the arguments given by graphql-go.
+func (g *Generator) writeArgs(buf *bytes.Buffer, obj *GqlModel, field *ModelVar) error {
+	name := argsName(obj, field)
+	fmt.Fprintf(buf, "// %s holds the arguments of %s.%s.\n", name, obj.Name, field.Name)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, arg := range field.Arg {
+		typ, err := g.modelType("", argType(&arg))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "%s %s\n", camelName(arg.Name, false), exprString(typ))
+	}
+	buf.WriteString("}\n\n")
+
+	var body bytes.Buffer
+	assigns := false
+	for _, arg := range field.Arg {
+		assign, err := g.writeDecode(&body, "", argType(&arg), fmt.Sprintf("args[%q]", arg.Name),
+			"result."+camelName(arg.Name, false), fmt.Sprintf("argument %s", arg.Name))
+		if err != nil {
+			return err
+		}
+		assigns = assigns || assign
+	}
+	fmt.Fprintf(buf, "func decode%s(args map[string]interface{}) (%s, error) {\n", name, name)
+	fmt.Fprintf(buf, "var result %s\n", name)
+	writeDecodeBody(buf, body.Bytes(), assigns)
+	return nil
+}
+
+// writeDecodeBody writes the statements of a decoder, declaring the err
+// variable when they assign it.
+func writeDecodeBody(buf *bytes.Buffer, body []byte, assigns bool) {
+	if assigns {
+		buf.WriteString("var err error\n")
+	}
+	buf.Write(body)
+	buf.WriteString("return result, nil\n}\n\n")
+}
+
+// writeDecoder writes the function decoding a value of the named type, as
+// given by graphql-go, into its model: a type assertion for scalars, a check
+// of the value for enums, which may be given as their model constant or
+// their name, and a decoding of each field into a new model for input
+// objects.
+func (g *Generator) writeDecoder(buf *bytes.Buffer, name string) error {
+	if goType, ok := g.modelTypes[name]; ok {
+		fmt.Fprintf(buf, "func decode%s(v interface{}) (%s, error) {\n", modelName(name), goType)
+		fmt.Fprintf(buf, "x, ok := v.(%s)\nif !ok {\n", goType)
+		fmt.Fprintf(buf, "return x, fmt.Errorf(\"expected %s, found %%T\", v)\n}\nreturn x, nil\n}\n\n", name)
+		return nil
+	}
+	def := g.doc.Type(name)
+	goType := modelName(name)
+	switch def.Kind {
+	case KindEnum:
+		fmt.Fprintf(buf, "func decode%s(v interface{}) (%s, error) {\n", goType, goType)
+		fmt.Fprintf(buf, "var x %s\nswitch v := v.(type) {\n", goType)
+		fmt.Fprintf(buf, "case %s:\nx = v\ncase string:\nx = %s(v)\ndefault:\n", goType, goType)
+		fmt.Fprintf(buf, "return \"\", fmt.Errorf(\"expected %s, found %%T\", v)\n}\n", name)
+		buf.WriteString("switch x {\ncase ")
+		for i, value := range def.Values {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(enumConst(name, value.Name))
+		}
+		buf.WriteString(":\nreturn x, nil\n}\n")
+		fmt.Fprintf(buf, "return \"\", fmt.Errorf(\"invalid %s value %%q\", x)\n}\n\n", name)
+	case KindInput:
+		fmt.Fprintf(buf, "func decode%s(v interface{}) (%s, error) {\n", goType, goType)
+		fmt.Fprintf(buf, "var result %s\n", goType)
+		buf.WriteString("fields, ok := v.(map[string]interface{})\nif !ok {\n")
+		fmt.Fprintf(buf, "return result, fmt.Errorf(\"expected %s, found %%T\", v)\n}\n", name)
+		var body bytes.Buffer
+		assigns := false
+		for _, field := range def.Variables {
+			assign, err := g.writeDecode(&body, name, fieldType(&field), fmt.Sprintf("fields[%q]", field.Name),
+				"result."+camelName(field.Name, false), fmt.Sprintf("field %s", field.Name))
+			if err != nil {
+				return err
+			}
+			assigns = assigns || assign
+		}
+		writeDecodeBody(buf, body.Bytes(), assigns)
+	}
+	return nil
+}
+
+// writeDecode writes statements decoding the value src of type typ into
+// dst, a field of the model of owner or of an args struct if owner is "",
+// returning from the enclosing decoder with an error naming what was
+// decoded if it does not fit. It reports whether the statements assign the
+// err variable of the decoder.
+func (g *Generator) writeDecode(buf *bytes.Buffer, owner string, typ gqlType, src string, dst string, what string) (bool, error) {
+	if !typ.list {
+		pointer := !typ.required || g.byPointer(owner, typ.name())
+		return g.writeDecodeValue(buf, typ.name(), typ.required, pointer, src, dst, strconv.Quote(what+": %v"), "err"), nil
+	}
+	elt, err := g.modelType("", gqlType{tok: typ.tok, lit: typ.lit, required: typ.itemRequired})
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(buf, "if items, ok := %s.([]interface{}); ok {\n", src)
+	fmt.Fprintf(buf, "%s = make([]%s, len(items))\n", dst, exprString(elt))
+	buf.WriteString("for i, item := range items {\n")
+	assigns := g.writeDecodeValue(buf, typ.name(), typ.itemRequired, !typ.itemRequired, "item", dst+"[i]", strconv.Quote(what+": item %d: %v"), "i, err")
+	buf.WriteString("}\n")
+	if typ.required {
+		buf.WriteString("} else {\n")
+	} else {
+		fmt.Fprintf(buf, "} else if %s != nil {\n", src)
+	}
+	fmt.Fprintf(buf, "return result, fmt.Errorf(%s, %s)\n}\n", strconv.Quote(what+": expected a list, found %T"), src)
+	return assigns, nil
+}
+
+// writeDecodeValue writes statements decoding the value src of the named
+// type into dst, which holds a pointer to the value if pointer is set,
+// formatting errors with format and args. It reports whether the
+// statements assign the err variable of the decoder.
+func (g *Generator) writeDecodeValue(buf *bytes.Buffer, name string, required bool, pointer bool, src string, dst string, format string, args string) bool {
+	_, scalar := g.modelTypes[name]
+	if def := g.doc.Type(name); !scalar && (def == nil || def.Kind == KindScalar) {
+		// Scalars without a model type are passed through.
+		fmt.Fprintf(buf, "%s = %s\n", dst, src)
+		return false
+	}
+	switch {
+	case !pointer:
+		fmt.Fprintf(buf, "if %s, err = decode%s(%s); err != nil {\n", dst, modelName(name), src)
+		fmt.Fprintf(buf, "return result, fmt.Errorf(%s, %s)\n}\n", format, args)
+		return true
+	case required:
+		// A non-null field held by pointer, as the model would otherwise
+		// contain itself.
+		fmt.Fprintf(buf, "if x, err := decode%s(%s); err != nil {\n", modelName(name), src)
+		fmt.Fprintf(buf, "return result, fmt.Errorf(%s, %s)\n} else {\n%s = &x\n}\n", format, args, dst)
+		return false
+	}
+	fmt.Fprintf(buf, "if %s != nil {\n", src)
+	fmt.Fprintf(buf, "x, err := decode%s(%s)\nif err != nil {\n", modelName(name), src)
+	fmt.Fprintf(buf, "return result, fmt.Errorf(%s, %s)\n}\n", format, args)
+	fmt.Fprintf(buf, "%s = &x\n}\n", dst)
+	return false
+}
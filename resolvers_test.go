@@ -0,0 +1,204 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_GenerateResolvers(t *testing.T) {
+	testString := `package models
+type Query {
+  user(id: ID!): User
+  me: User
+  search(filter: Filter!, ids: [ID!]): [User!]!
+}
+input Filter { role: Role, limit: Int! }
+enum Role { ADMIN USER }
+type User {
+  id: ID!
+  nick(upper: Boolean = false): String
+}`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString), WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"\t\"context\"\n\t\"fmt\"\n\n",
+		"\t\t\tResolve: resolveQueryUser,\n",
+		"\t\t\tType:    UserType,\n\t\t\tResolve: resolveQueryMe,\n",
+		"\t\t\tResolve: resolveUserNick,\n",
+		`type Resolver interface {
+	Query() QueryResolver
+	User() UserResolver
+}`,
+		`type QueryResolver interface {
+	User(ctx context.Context, args QueryUserArgs) (*User, error)
+	Me(ctx context.Context) (*User, error)
+	Search(ctx context.Context, args QuerySearchArgs) ([]User, error)
+}`,
+		`type UserResolver interface {
+	Nick(ctx context.Context, obj *User, args UserNickArgs) (*string, error)
+}`,
+		`type UserNickArgs struct {
+	Upper *bool
+}`,
+		`func resolveUserNick(p graphql.ResolveParams) (interface{}, error) {
+	obj, err := sourceUser(p.Source)
+	if err != nil {
+		return nil, err
+	}
+	args, err := decodeUserNickArgs(p.Args)
+	if err != nil {
+		return nil, fmt.Errorf("User.nick: %v", err)
+	}
+	return resolver.User().Nick(p.Context, obj, args)
+}`,
+		`func decodeQuerySearchArgs(args map[string]interface{}) (QuerySearchArgs, error) {
+	var result QuerySearchArgs
+	var err error
+	if result.Filter, err = decodeFilter(args["filter"]); err != nil {
+		return result, fmt.Errorf("argument filter: %v", err)
+	}
+	if items, ok := args["ids"].([]interface{}); ok {
+		result.Ids = make([]string, len(items))
+		for i, item := range items {
+			if result.Ids[i], err = decodeID(item); err != nil {
+				return result, fmt.Errorf("argument ids: item %d: %v", i, err)
+			}
+		}
+	} else if args["ids"] != nil {
+		return result, fmt.Errorf("argument ids: expected a list, found %T", args["ids"])
+	}
+	return result, nil
+}`,
+		`type QuerySearchArgs struct {
+	Filter Filter
+	Ids    []string
+}`,
+		`func decodeFilter(v interface{}) (Filter, error) {
+	var result Filter
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		return result, fmt.Errorf("expected Filter, found %T", v)
+	}
+	var err error
+	if fields["role"] != nil {
+		x, err := decodeRole(fields["role"])
+		if err != nil {
+			return result, fmt.Errorf("field role: %v", err)
+		}
+		result.Role = &x
+	}
+	if result.Limit, err = decodeInt(fields["limit"]); err != nil {
+		return result, fmt.Errorf("field limit: %v", err)
+	}
+	return result, nil
+}`,
+		`func decodeRole(v interface{}) (Role, error) {
+	var x Role
+	switch v := v.(type) {
+	case Role:
+		x = v
+	case string:
+		x = Role(v)
+	default:
+		return "", fmt.Errorf("expected Role, found %T", v)
+	}
+	switch x {
+	case RoleAdmin, RoleUser:
+		return x, nil
+	}
+	return "", fmt.Errorf("invalid Role value %q", x)
+}`,
+		`func decodeInt(v interface{}) (int, error) {
+	x, ok := v.(int)
+	if !ok {
+		return x, fmt.Errorf("expected Int, found %T", v)
+	}
+	return x, nil
+}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Generate returned\n%s\nwhich does not contain\n%s", out, want)
+		}
+	}
+	if strings.Contains(out, "Resolve: resolveUserID") {
+		t.Errorf("Generate wired a resolver to User.id, which has no arguments")
+	}
+
+	err = Generate(&b, strings.NewReader(testString), WithNameSuffix("Type"), WithResolvers())
+	if err == nil || err.Error() != "resolvers return the models, so they require mode all" {
+		t.Errorf("Generate did not require ModeAll for resolvers, returned %v", err)
+	}
+
+	testString = `package models
+type Query { user: String }
+type QueryResolver { id: ID }`
+	err = Generate(&b, strings.NewReader(testString), WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers())
+	if err == nil || err.Error() != "2:1: the model of type QueryResolver and the resolver of type Query both generate the Go name QueryResolver" {
+		t.Errorf("Generate did not report the colliding resolver, returned %v", err)
+	}
+}
+
+func Test_GenerateResolversDecodeEnums(t *testing.T) {
+	testString := `package models
+type Query {
+  users(role: Role!, filter: Filter): [String!]!
+}
+input Filter { role: Role = ADMIN, roles: [Role!] }
+enum Role { ADMIN USER }`
+	const mainSrc = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"example.com/generated/models"
+	"github.com/graphql-go/graphql"
+)
+
+type resolver struct{}
+
+func (resolver) Query() models.QueryResolver { return resolver{} }
+
+func (resolver) Users(ctx context.Context, args models.QueryUsersArgs) ([]string, error) {
+	users := []string{string(args.Role)}
+	if args.Filter != nil {
+		users = append(users, string(*args.Filter.Role))
+		for _, role := range args.Filter.Roles {
+			users = append(users, string(role))
+		}
+	}
+	return users, nil
+}
+
+func main() {
+	models.SetResolver(resolver{})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: models.QueryType})
+	if err != nil {
+		panic(err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: "{ a: users(role: USER) b: users(role: ADMIN, filter: {roles: [USER]}) }",
+	})
+	out, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+}
+`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString), WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := runGenerated(t, b.String(), mainSrc)
+	if want := `{"data":{"a":["USER"],"b":["ADMIN","ADMIN","USER"]}}` + "\n"; out != want {
+		t.Errorf("generated resolvers returned %s, expected %s", out, want)
+	}
+}
@@ -0,0 +1,611 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+)
+
+// goKinds maps the graphql-go constructors to the kind of type they define.
+var goKinds = map[string]Kind{
+	"NewObject":      KindObject,
+	"NewInterface":   KindInterface,
+	"NewInputObject": KindInput,
+	"NewEnum":        KindEnum,
+	"NewScalar":      KindScalar,
+	"NewUnion":       KindUnion,
+}
+
+// goScalars maps the scalars predefined by graphql-go to their tokens.
+var goScalars = map[string]Token{
+	"String":   STRING,
+	"Int":      INT,
+	"Float":    FLOAT,
+	"Boolean":  BOOLEAN,
+	"ID":       ID,
+	"DateTime": IDENT,
+}
+
+// ParseGoFiles reads the graphql-go types defined in the Go source files at
+// paths, whether generated or written by hand, and returns them as a
+// document, so that a service can move to generating from a schema. Each
+// graphql.NewObject, NewInterface, NewInputObject, NewEnum, NewScalar and
+// NewUnion call with a literal config defines a type; types may refer to
+// each other through the variables they are assigned to, in any of the
+// files. Fields added to those variables with AddFieldConfig, as generated
+// for self-referencing types, follow the fields of the config. The files
+// are analyzed statically: configs built at run time are reported as
+// errors.
+func ParseGoFiles(paths ...string) (*Document, error) {
+	r := &goReader{
+		fset: token.NewFileSet(),
+		vars: make(map[string]ast.Expr),
+		defs: make(map[*ast.CallExpr]*GqlModel),
+		pkgs: make(map[*ast.CallExpr]string),
+		adds: make(map[*ast.CallExpr][]*ast.CallExpr),
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		file, err := parser.ParseFile(r.fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	// Name every definition first, so that references may come before
+	// the definitions they refer to.
+	var calls, adds []*ast.CallExpr
+	for _, file := range files {
+		pkg := graphqlName(file)
+		if pkg == "" {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if i < len(n.Values) {
+						r.vars[name.Name] = n.Values[i]
+					}
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && i < len(n.Rhs) && len(n.Lhs) == len(n.Rhs) {
+						r.vars[ident.Name] = n.Rhs[i]
+					}
+				}
+			case *ast.CallExpr:
+				if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "AddFieldConfig" {
+					adds = append(adds, n)
+				}
+				if name := r.selName(pkg, n.Fun); name != "" {
+					if _, ok := goKinds[name]; ok {
+						calls = append(calls, n)
+						r.pkgs[n] = pkg
+					}
+				}
+			}
+			return true
+		})
+	}
+	doc := &Document{}
+	for _, call := range calls {
+		r.pkg = r.pkgs[call]
+		obj, err := r.declare(call)
+		if err != nil {
+			return nil, err
+		}
+		r.defs[call] = obj
+	}
+	for _, add := range adds {
+		// Calls on anything but the variable of a type are not ours.
+		call, ok := r.deref(add.Fun.(*ast.SelectorExpr).X).(*ast.CallExpr)
+		if !ok || r.defs[call] == nil {
+			continue
+		}
+		if len(add.Args) != 2 {
+			return nil, r.errorf(add, "expected a field name and config")
+		}
+		r.adds[call] = append(r.adds[call], add)
+	}
+	for _, call := range calls {
+		r.pkg = r.pkgs[call]
+		if err := r.define(call); err != nil {
+			return nil, err
+		}
+		doc.Types = append(doc.Types, *r.defs[call])
+	}
+	for _, name := range r.scalars {
+		if doc.Type(name) == nil {
+			doc.Types = append(doc.Types, GqlModel{Name: name, Kind: KindScalar})
+		}
+	}
+	for _, d := range r.defaults {
+		v, err := r.goValue(doc, d.expr, d.typ)
+		if err != nil {
+			return nil, err
+		}
+		*d.text = v.String()
+	}
+	return doc, nil
+}
+
+// goReader collects the graphql-go definitions of Go files.
+type goReader struct {
+	fset *token.FileSet
+	// pkg is the name the file being read imports graphql-go as, and pkgs
+	// that of the file of each constructor call.
+	pkg  string
+	pkgs map[*ast.CallExpr]string
+	// vars maps variable names to the expressions assigned to them.
+	vars map[string]ast.Expr
+	// defs maps the constructor calls to the types they define, and adds
+	// to the AddFieldConfig calls on the variables holding them.
+	defs map[*ast.CallExpr]*GqlModel
+	adds map[*ast.CallExpr][]*ast.CallExpr
+	// scalars are the graphql-go scalars referred to that the schema must
+	// declare, and defaults the default values to convert once every type
+	// is known.
+	scalars  []string
+	defaults []goDefault
+}
+
+// goDefault is a default value waiting to be converted to schema notation.
+type goDefault struct {
+	text *string
+	expr ast.Expr
+	typ  gqlType
+}
+
+// graphqlName returns the name file imports graphql-go as, or "" if it does
+// not import it.
+func graphqlName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == graphqlPath {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return "graphql"
+		}
+	}
+	return ""
+}
+
+// errorf returns an error at the position of node.
+func (r *goReader) errorf(node ast.Node, format string, a ...interface{}) error {
+	return fmt.Errorf("%s: %s", r.fset.Position(node.Pos()), fmt.Sprintf(format, a...))
+}
+
+// source returns the Go source of node.
+func (r *goReader) source(node ast.Node) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, r.fset, node)
+	return buf.String()
+}
+
+// selName returns name if expr is the selector pkg.name, or "".
+func (r *goReader) selName(pkg string, expr ast.Expr) string {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg {
+			return sel.Sel.Name
+		}
+	}
+	return ""
+}
+
+// deref returns the expression assigned to expr if it is a variable, and
+// the operand of expr if it takes an address or is in parentheses.
+func (r *goReader) deref(expr ast.Expr) ast.Expr {
+	for seen := 0; seen < 100; seen++ {
+		switch x := expr.(type) {
+		case *ast.Ident:
+			value, ok := r.vars[x.Name]
+			if !ok {
+				return expr
+			}
+			expr = value
+		case *ast.UnaryExpr:
+			if x.Op != token.AND {
+				return expr
+			}
+			expr = x.X
+		case *ast.ParenExpr:
+			expr = x.X
+		default:
+			return expr
+		}
+	}
+	return expr
+}
+
+// literal returns the composite literal expr refers to. Thunks, functions
+// or conversions of functions returning a literal, are unwrapped.
+func (r *goReader) literal(expr ast.Expr) (*ast.CompositeLit, error) {
+	switch x := r.deref(expr).(type) {
+	case *ast.CompositeLit:
+		return x, nil
+	case *ast.CallExpr:
+		if len(x.Args) == 1 {
+			return r.literal(x.Args[0])
+		}
+	case *ast.FuncLit:
+		if n := len(x.Body.List); n > 0 {
+			if ret, ok := x.Body.List[n-1].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+				return r.literal(ret.Results[0])
+			}
+		}
+	}
+	return nil, r.errorf(expr, "cannot read the value of %s statically", r.source(expr))
+}
+
+// elements returns the elements of the composite literal expr refers to by
+// key, in source order.
+func (r *goReader) elements(expr ast.Expr) ([]string, map[string]ast.Expr, error) {
+	lit, err := r.literal(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keys []string
+	elts := make(map[string]ast.Expr)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, nil, r.errorf(elt, "expected a keyed element, found %s", r.source(elt))
+		}
+		var key string
+		switch k := kv.Key.(type) {
+		case *ast.Ident:
+			key = k.Name
+		case *ast.BasicLit:
+			if k.Kind == token.STRING {
+				key, _ = strconv.Unquote(k.Value)
+			}
+		}
+		if key == "" {
+			return nil, nil, r.errorf(kv.Key, "expected a constant key, found %s", r.source(kv.Key))
+		}
+		keys = append(keys, key)
+		elts[key] = kv.Value
+	}
+	return keys, elts, nil
+}
+
+// str returns the string constant expr refers to.
+func (r *goReader) str(expr ast.Expr) (string, error) {
+	if lit, ok := r.deref(expr).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		return strconv.Unquote(lit.Value)
+	}
+	return "", r.errorf(expr, "expected a string constant, found %s", r.source(expr))
+}
+
+// declare returns the type defined by call with its name and kind.
+func (r *goReader) declare(call *ast.CallExpr) (*GqlModel, error) {
+	if len(call.Args) != 1 {
+		return nil, r.errorf(call, "expected one config argument")
+	}
+	_, config, err := r.elements(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	nameExpr, ok := config["Name"]
+	if !ok {
+		return nil, r.errorf(call, "type has no Name")
+	}
+	name, err := r.str(nameExpr)
+	if err != nil {
+		return nil, err
+	}
+	pos := r.fset.Position(call.Pos())
+	return &GqlModel{
+		Name: name,
+		Kind: goKinds[r.selName(r.pkg, call.Fun)],
+		File: pos.Filename,
+		Pos:  Pos{Line: pos.Line, Column: pos.Column},
+	}, nil
+}
+
+// define reads the fields, values, interfaces or members of the type
+// defined by call from its config.
+func (r *goReader) define(call *ast.CallExpr) error {
+	obj := r.defs[call]
+	_, config, err := r.elements(call.Args[0])
+	if err != nil {
+		return err
+	}
+	switch obj.Kind {
+	case KindObject, KindInterface, KindInput:
+		var names []string
+		elts := make(map[string]ast.Expr)
+		if fields, ok := config["Fields"]; ok {
+			if names, elts, err = r.elements(fields); err != nil {
+				return err
+			}
+		}
+		for _, add := range r.adds[call] {
+			name, err := r.str(add.Args[0])
+			if err != nil {
+				return err
+			}
+			if _, ok := elts[name]; !ok {
+				names = append(names, name)
+			}
+			elts[name] = add.Args[1]
+		}
+		if len(names) > 0 {
+			if obj.Variables, err = r.fields(names, elts, obj.Kind == KindInput); err != nil {
+				return err
+			}
+		}
+		if interfaces, ok := config["Interfaces"]; ok {
+			if obj.Interfaces, err = r.typeNames(interfaces); err != nil {
+				return err
+			}
+		}
+	case KindUnion:
+		if types, ok := config["Types"]; ok {
+			if obj.Types, err = r.typeNames(types); err != nil {
+				return err
+			}
+		}
+	case KindEnum:
+		if values, ok := config["Values"]; ok {
+			names, elts, err := r.elements(values)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				value := EnumValue{Name: name, Pos: r.pos(elts[name])}
+				_, valueConfig, err := r.elements(elts[name])
+				if err != nil {
+					return err
+				}
+				if value.Directives, err = r.deprecated(valueConfig); err != nil {
+					return err
+				}
+				obj.Values = append(obj.Values, value)
+			}
+		}
+	}
+	return nil
+}
+
+// pos returns the position of node.
+func (r *goReader) pos(node ast.Node) Pos {
+	pos := r.fset.Position(node.Pos())
+	return Pos{Line: pos.Line, Column: pos.Column}
+}
+
+// fields reads the configs of the named fields, as given by a graphql.Fields
+// or graphql.InputObjectConfigFieldMap.
+func (r *goReader) fields(names []string, elts map[string]ast.Expr, input bool) ([]ModelVar, error) {
+	vars := make([]ModelVar, len(names))
+	for i, name := range names {
+		field := &vars[i]
+		field.Name, field.Pos = name, r.pos(elts[name])
+		_, config, err := r.elements(elts[name])
+		if err != nil {
+			return nil, err
+		}
+		typ, err := r.typeRef(config["Type"], elts[name])
+		if err != nil {
+			return nil, err
+		}
+		field.Tok, field.Lit, field.List, field.ItemRequired, field.Required = typ.tok, typ.lit, typ.list, typ.itemRequired, typ.required
+		if value, ok := config["DefaultValue"]; ok && input {
+			r.defaults = append(r.defaults, goDefault{&field.Default, value, *typ})
+		}
+		if field.Directives, err = r.deprecated(config); err != nil {
+			return nil, err
+		}
+		if args, ok := config["Args"]; ok && !input {
+			argNames, argElts, err := r.elements(args)
+			if err != nil {
+				return nil, err
+			}
+			field.Arg = make([]GqlArg, len(argNames))
+			for j, argName := range argNames {
+				arg := &field.Arg[j]
+				arg.Name, arg.Pos = argName, r.pos(argElts[argName])
+				_, argConfig, err := r.elements(argElts[argName])
+				if err != nil {
+					return nil, err
+				}
+				typ, err := r.typeRef(argConfig["Type"], argElts[argName])
+				if err != nil {
+					return nil, err
+				}
+				arg.Tok, arg.Lit, arg.List, arg.ItemRequired, arg.Required = typ.tok, typ.lit, typ.list, typ.itemRequired, typ.required
+				if value, ok := argConfig["DefaultValue"]; ok {
+					r.defaults = append(r.defaults, goDefault{&arg.Default, value, *typ})
+				}
+			}
+		}
+	}
+	return vars, nil
+}
+
+// deprecated returns the @deprecated directive for the DeprecationReason of
+// a field or enum value config, if it has one.
+func (r *goReader) deprecated(config map[string]ast.Expr) ([]Directive, error) {
+	expr, ok := config["DeprecationReason"]
+	if !ok {
+		return nil, nil
+	}
+	reason, err := r.str(expr)
+	if err != nil || reason == "" {
+		return nil, err
+	}
+	return []Directive{deprecatedDirective(reason)}, nil
+}
+
+// deprecatedDirective returns the @deprecated directive giving reason,
+// which is left out if it is the default.
+func deprecatedDirective(reason string) Directive {
+	directive := Directive{Name: "deprecated"}
+	if reason != "No longer supported" {
+		directive.Args = []DirectiveArg{{Name: "reason", Value: quote(reason)}}
+	}
+	return directive
+}
+
+// typeNames reads a list of types, such as the Interfaces of an object.
+func (r *goReader) typeNames(expr ast.Expr) ([]string, error) {
+	lit, err := r.literal(expr)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, elt := range lit.Elts {
+		typ, err := r.typeRef(elt, elt)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, typ.name())
+	}
+	return names, nil
+}
+
+// typeRef reads the type expr refers to, wrapped in graphql.NewList and
+// graphql.NewNonNull. node locates errors if expr is missing.
+func (r *goReader) typeRef(expr ast.Expr, node ast.Node) (*gqlType, error) {
+	if expr == nil {
+		return nil, r.errorf(node, "field has no Type")
+	}
+	if name := r.selName(r.pkg, expr); name != "" {
+		tok, ok := goScalars[name]
+		if !ok {
+			return nil, r.errorf(expr, "unknown graphql-go type %s", r.source(expr))
+		}
+		if tok == IDENT {
+			r.useScalar(name)
+		}
+		return &gqlType{tok: tok, lit: name}, nil
+	}
+	switch x := r.deref(expr).(type) {
+	case *ast.CallExpr:
+		if def, ok := r.defs[x]; ok {
+			return &gqlType{tok: IDENT, lit: def.Name}, nil
+		}
+		switch r.selName(r.pkg, x.Fun) {
+		case "NewNonNull":
+			if len(x.Args) == 1 {
+				typ, err := r.typeRef(x.Args[0], x)
+				if err != nil {
+					return nil, err
+				}
+				typ.required = true
+				return typ, nil
+			}
+		case "NewList":
+			if len(x.Args) == 1 {
+				typ, err := r.typeRef(x.Args[0], x)
+				if err != nil {
+					return nil, err
+				}
+				if typ.list {
+					return nil, r.errorf(x, "nested lists are not supported")
+				}
+				return &gqlType{tok: typ.tok, lit: typ.lit, list: true, itemRequired: typ.required}, nil
+			}
+		}
+	case *ast.SelectorExpr:
+		if r.selName(r.pkg, x) != "" {
+			return r.typeRef(x, node)
+		}
+	}
+	return nil, r.errorf(expr, "cannot resolve type %s statically", r.source(expr))
+}
+
+// useScalar records that the schema must declare the named scalar.
+func (r *goReader) useScalar(name string) {
+	for _, scalar := range r.scalars {
+		if scalar == name {
+			return
+		}
+	}
+	r.scalars = append(r.scalars, name)
+}
+
+// goValue converts the Go default value expr of type typ to a value.
+func (r *goReader) goValue(doc *Document, expr ast.Expr, typ gqlType) (*value, error) {
+	expr = r.deref(expr)
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		switch x.Kind {
+		case token.INT:
+			return &value{kind: intValue, lit: x.Value}, nil
+		case token.FLOAT:
+			return &value{kind: floatValue, lit: x.Value}, nil
+		case token.STRING:
+			s, err := strconv.Unquote(x.Value)
+			if err != nil {
+				return nil, r.errorf(x, "invalid string %s", x.Value)
+			}
+			if def := doc.Type(typ.name()); def != nil && def.Kind == KindEnum {
+				return &value{kind: enumValue, lit: s}, nil
+			}
+			return &value{kind: stringValue, lit: quote(s)}, nil
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := x.X.(*ast.BasicLit); ok && x.Op == token.SUB && (lit.Kind == token.INT || lit.Kind == token.FLOAT) {
+			v, err := r.goValue(doc, lit, typ)
+			if err != nil {
+				return nil, err
+			}
+			v.lit = "-" + v.lit
+			return v, nil
+		}
+	case *ast.Ident:
+		switch x.Name {
+		case "true", "false":
+			return &value{kind: booleanValue, lit: x.Name}, nil
+		case "nil":
+			return &value{kind: nullValue, lit: "null"}, nil
+		}
+	case *ast.CompositeLit:
+		switch x.Type.(type) {
+		case *ast.ArrayType:
+			list := &value{kind: listValue}
+			item := gqlType{tok: typ.tok, lit: typ.lit, required: typ.itemRequired}
+			for _, elt := range x.Elts {
+				v, err := r.goValue(doc, elt, item)
+				if err != nil {
+					return nil, err
+				}
+				list.list = append(list.list, v)
+			}
+			return list, nil
+		case *ast.MapType:
+			object := &value{kind: objectValue}
+			def := doc.Type(typ.name())
+			for _, elt := range x.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return nil, r.errorf(elt, "expected a keyed element, found %s", r.source(elt))
+				}
+				name, err := r.str(kv.Key)
+				if err != nil {
+					return nil, err
+				}
+				var fieldTyp gqlType
+				if def != nil {
+					if field := def.Field(name); field != nil {
+						fieldTyp = fieldType(field)
+					}
+				}
+				v, err := r.goValue(doc, kv.Value, fieldTyp)
+				if err != nil {
+					return nil, err
+				}
+				object.fields = append(object.fields, objectField{name: name, value: v})
+			}
+			return object, nil
+		}
+	}
+	return nil, r.errorf(expr, "cannot convert default value %s", r.source(expr))
+}
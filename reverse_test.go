@@ -0,0 +1,160 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ParseGoFiles(t *testing.T) {
+	dir := t.TempDir()
+	types := writeFile(t, dir, "types.go", `package legacy
+
+import gql "github.com/graphql-go/graphql"
+
+var userFields = gql.Fields{
+	"id":      &gql.Field{Type: gql.NewNonNull(gql.ID)},
+	"friends": &gql.Field{Type: gql.NewList(gql.NewNonNull(userType))},
+	"role": &gql.Field{
+		Type:              roleType,
+		DeprecationReason: "Use roles.",
+	},
+}
+
+var userType *gql.Object
+
+func init() {
+	userType = gql.NewObject(gql.ObjectConfig{
+		Name:   "User",
+		Fields: gql.FieldsThunk(func() gql.Fields { return userFields }),
+	})
+}
+`)
+	schema := writeFile(t, dir, "schema.go", `package legacy
+
+import gql "github.com/graphql-go/graphql"
+
+var queryType = gql.NewObject(gql.ObjectConfig{
+	Name: "Query",
+	Fields: gql.Fields{
+		"users": &gql.Field{
+			Type: gql.NewList(userType),
+			Args: gql.FieldConfigArgument{
+				"role":  &gql.ArgumentConfig{Type: roleType, DefaultValue: "ADMIN"},
+				"first": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 10},
+				"since": &gql.ArgumentConfig{Type: gql.DateTime},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) { return nil, nil },
+		},
+	},
+})
+
+var roleType = gql.NewEnum(gql.EnumConfig{
+	Name: "Role",
+	Values: gql.EnumValueConfigMap{
+		"ADMIN": &gql.EnumValueConfig{Value: 1},
+		"USER":  &gql.EnumValueConfig{Value: 2},
+	},
+})
+`)
+	doc, err := ParseGoFiles(types, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Fprint(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	want := `type User {
+  id: ID!
+  friends: [User!]
+  role: Role @deprecated(reason: "Use roles.")
+}
+
+type Query {
+  users(role: Role = ADMIN, first: Int = 10, since: DateTime): [User]
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+scalar DateTime
+`
+	if b.String() != want {
+		t.Errorf("ParseGoFiles read\n%s\ninstead of\n%s", b.String(), want)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	dynamic := writeFile(t, dir, "dynamic.go", `package legacy
+
+import "github.com/graphql-go/graphql"
+
+var fishType = graphql.NewObject(graphql.ObjectConfig{Name: name()})
+`)
+	_, err = ParseGoFiles(dynamic)
+	if err == nil || err.Error() != dynamic+":5:61: expected a string constant, found name()" {
+		t.Errorf("ParseGoFiles did not report the dynamic name, returned %v", err)
+	}
+}
+
+func Test_ParseGoFilesRoundTrip(t *testing.T) {
+	testString := `interface Node {
+  parent: Node
+}
+
+type User implements Node {
+  parent: Node
+  name: String!
+  friends: [User!]
+  role: Role
+}
+
+input Filter {
+  role: Role = ADMIN
+  or: [Filter!]
+}
+
+type Query {
+  users(filter: Filter, first: Int = 10): [User]
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+union Result = User
+`
+	dir := t.TempDir()
+	for _, opts := range [][]Option{
+		{WithStyle(StyleVar)},
+		{WithStyle(StyleThunk)},
+		{WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers()},
+	} {
+		var b strings.Builder
+		if err := Generate(&b, strings.NewReader("package models\n"+testString), opts...); err != nil {
+			t.Fatal(err)
+		}
+		doc, err := ParseGoFiles(writeFile(t, dir, "models.go", b.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var printed strings.Builder
+		if err := Fprint(&printed, doc); err != nil {
+			t.Fatal(err)
+		}
+		if printed.String() != testString {
+			t.Errorf("ParseGoFiles read\n%s\ninstead of\n%s", printed.String(), testString)
+		}
+		parsed, err := NewParser(strings.NewReader(printed.String())).ParseDocument()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := parsed.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MismatchError reports a method of a resolver file whose signature no
+// longer matches the resolver interface generated from the schema.
+type MismatchError struct {
+	File   string // name of the resolver file
+	Pos    Pos
+	Method string // receiver and method name, as queryResolver.User
+	Have   string // the parameter and result types of the method
+	Want   string // those required by the schema
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s:%s: method %s has signature %s, expected %s", e.File, e.Pos, e.Method, e.Have, e.Want)
+}
+
+// ScaffoldFiles reads the schema files at paths and returns the resolver
+// file at output, which need not exist yet, with a stub added for each
+// resolver method it lacks. Stubs return a "not implemented" error; code
+// already in the file is kept as it is. The file declares rootResolver,
+// implementing Resolver, and a type per resolver interface, such as
+// queryResolver for QueryResolver. Methods whose parameter or result types
+// differ from those of the interface are returned as mismatches, to be
+// fixed by hand.
+func (g *Generator) ScaffoldFiles(output string, paths ...string) ([]byte, []*MismatchError, error) {
+	doc, _, err := readFiles(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing, err := ioutil.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	return g.scaffold(output, existing, doc)
+}
+
+// scaffold returns existing, the content of the resolver file named name,
+// with the stubs doc requires added.
+func (g *Generator) scaffold(name string, existing []byte, doc *Document) ([]byte, []*MismatchError, error) {
+	gen := *g
+	gen.mode, gen.resolvers = ModeAll, true
+	g, packageName, err := gen.prepare(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	types := make(map[string]bool)
+	methods := make(map[string]*ast.FuncDecl)
+	if len(bytes.TrimSpace(existing)) == 0 {
+		existing = []byte(fmt.Sprintf("package %s\n", packageName))
+	}
+	file, err := parser.ParseFile(fset, name, existing, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					types[spec.Name.Name] = true
+				}
+			}
+		case *ast.FuncDecl:
+			if recv := receiverName(decl); recv != "" {
+				methods[recv+"."+decl.Name.Name] = decl
+			}
+		}
+	}
+
+	var stubs bytes.Buffer
+	var mismatches []*MismatchError
+	if !types["rootResolver"] {
+		stubs.WriteString("// rootResolver implements Resolver.\ntype rootResolver struct{}\n\n")
+	}
+	for i := range g.doc.Types {
+		obj := &g.doc.Types[i]
+		fields := g.resolvedFields(obj)
+		if len(fields) == 0 {
+			continue
+		}
+		recv := implName(obj)
+		if methods["rootResolver."+modelName(obj.Name)] == nil {
+			fmt.Fprintf(&stubs, "func (rootResolver) %s() %s {\nreturn %s{}\n}\n\n", modelName(obj.Name), resolverName(obj), recv)
+		}
+		if !types[recv] {
+			fmt.Fprintf(&stubs, "// %s implements %s.\ntype %s struct{}\n\n", recv, resolverName(obj), recv)
+		}
+		for _, field := range fields {
+			method, err := g.resolverMethod(obj, &field)
+			if err != nil {
+				return nil, nil, err
+			}
+			decl := methods[recv+"."+method.name]
+			if decl == nil {
+				fmt.Fprintf(&stubs, "func (%s) %s%s {\n", recv, method.name, method.signature(true))
+				fmt.Fprintf(&stubs, "return %s, errors.New(\"not implemented: %s.%s\")\n}\n\n", g.zeroValue(fieldType(&field)), obj.Name, field.Name)
+				continue
+			}
+			if have := funcSignature(fset, decl.Type); have != method.signature(false) {
+				pos := fset.Position(decl.Name.Pos())
+				mismatches = append(mismatches, &MismatchError{
+					File:   name,
+					Pos:    Pos{Line: pos.Line, Column: pos.Column},
+					Method: recv + "." + method.name,
+					Have:   have,
+					Want:   method.signature(false),
+				})
+			}
+		}
+	}
+
+	src := append(bytes.TrimRight(existing, "\n"), "\n\n"...)
+	src = append(src, stubs.Bytes()...)
+	if src, err = formatSource(src); err != nil {
+		return nil, nil, err
+	}
+	if src, err = addImports(src, g.imports); err != nil {
+		return nil, nil, err
+	}
+	return src, mismatches, nil
+}
+
+// implName returns the name of the type implementing the resolver interface
+// of obj in a scaffolded file.
+func implName(obj *GqlModel) string {
+	name := resolverName(obj)
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// receiverName returns the name of the type of the receiver of a method, or
+// "" for a function.
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	typ := decl.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// funcSignature returns the parameter and result types of a function type
+// parsed into fset in Go notation, without names.
+func funcSignature(fset *token.FileSet, typ *ast.FuncType) string {
+	types := func(fields *ast.FieldList) string {
+		var list []string
+		if fields != nil {
+			for _, field := range fields.List {
+				n := len(field.Names)
+				if n == 0 {
+					n = 1
+				}
+				for i := 0; i < n; i++ {
+					var buf bytes.Buffer
+					printer.Fprint(&buf, fset, field.Type)
+					list = append(list, buf.String())
+				}
+			}
+		}
+		return "(" + strings.Join(list, ", ") + ")"
+	}
+	return types(typ.Params) + " " + types(typ.Results)
+}
+
+// zeroValue returns the Go expression for the zero value of the model type
+// of typ.
+func (g *Generator) zeroValue(typ gqlType) string {
+	// Lists are slices and nullable values pointers or interfaces.
+	name := typ.name()
+	if typ.list || !typ.required {
+		return "nil"
+	}
+	if goType, ok := g.modelTypes[name]; ok {
+		switch goType {
+		case "string":
+			return `""`
+		case "bool":
+			return "false"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+			return "0"
+		}
+		if goType == "interface{}" || strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+			return "nil"
+		}
+		// The type may be a struct or a named basic type; *new(T) is the
+		// zero value of either.
+		return "*new(" + goType + ")"
+	}
+	if def := g.doc.Type(name); def != nil {
+		switch def.Kind {
+		case KindEnum:
+			return `""`
+		case KindObject, KindInput:
+			return modelName(name) + "{}"
+		}
+	}
+	// Other scalars are interface{}, and interfaces and unions Go
+	// interfaces.
+	return "nil"
+}
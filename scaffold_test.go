@@ -0,0 +1,139 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Scaffold(t *testing.T) {
+	testString := `package models
+type Query {
+  user(id: ID!): User
+  count: Int!
+  role: Role!
+  me: User!
+  due: Date!
+  timeout: Duration!
+}
+scalar Date
+scalar Duration
+type User { id: ID!, name: String! }
+enum Role { ADMIN }`
+	existing := `package models
+
+import (
+	"context"
+	"fmt"
+)
+
+type rootResolver struct{ db string }
+
+func (rootResolver) Query() QueryResolver {
+	return queryResolver{}
+}
+
+type queryResolver struct{}
+
+// User looks the user up.
+func (queryResolver) User(ctx context.Context, args QueryUserArgs) (*User, error) {
+	return nil, fmt.Errorf("no user %s", args.ID)
+}
+
+func (queryResolver) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+`
+	opts := []Option{WithModelType("Date", "time.Time"), WithModelType("Duration", "time.Duration"),
+		WithImport("time", "time")}
+	doc, err := NewParser(strings.NewReader(testString)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, mismatches, err := NewGenerator(opts...).scaffold("resolvers.go", []byte(existing), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type rootResolver struct{ db string }
+
+func (rootResolver) Query() QueryResolver {
+	return queryResolver{}
+}
+
+type queryResolver struct{}
+
+// User looks the user up.
+func (queryResolver) User(ctx context.Context, args QueryUserArgs) (*User, error) {
+	return nil, fmt.Errorf("no user %s", args.ID)
+}
+
+func (queryResolver) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (queryResolver) Role(ctx context.Context) (Role, error) {
+	return "", errors.New("not implemented: Query.role")
+}
+
+func (queryResolver) Me(ctx context.Context) (User, error) {
+	return User{}, errors.New("not implemented: Query.me")
+}
+
+func (queryResolver) Due(ctx context.Context) (time.Time, error) {
+	return *new(time.Time), errors.New("not implemented: Query.due")
+}
+
+func (queryResolver) Timeout(ctx context.Context) (time.Duration, error) {
+	return *new(time.Duration), errors.New("not implemented: Query.timeout")
+}
+`
+	if string(src) != want {
+		t.Errorf("scaffold returned\n%s\ninstead of\n%s", src, want)
+	}
+	if len(mismatches) != 1 || mismatches[0].Error() != "resolvers.go:21:22: method queryResolver.Count has signature (context.Context) (int64, error), expected (context.Context) (int, error)" {
+		t.Errorf("scaffold reported mismatches %v", mismatches)
+	}
+
+	src, _, err = NewGenerator(opts...).scaffold("resolvers.go", nil, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// rootResolver implements Resolver.\ntype rootResolver struct{}\n",
+		"// queryResolver implements QueryResolver.\ntype queryResolver struct{}\n",
+		"\treturn 0, errors.New(\"not implemented: Query.count\")\n",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("scaffold returned\n%s\nwhich does not contain\n%s", src, want)
+		}
+	}
+
+	var b strings.Builder
+	if err := Generate(&b, strings.NewReader(testString), append(opts, WithMode(ModeAll), WithResolvers(),
+		WithScalar("Date", "graphql.DateTime"), WithScalar("Duration", "graphql.Int"))...); err != nil {
+		t.Fatal(err)
+	}
+	const mainSrc = `package main
+
+import (
+	"fmt"
+
+	"example.com/generated/models"
+)
+
+func main() {
+	fmt.Println(models.QueryType.Name())
+}
+`
+	if out := runGenerated(t, b.String(), mainSrc, string(src)); out != "Query\n" {
+		t.Errorf("scaffolded resolvers printed %q", out)
+	}
+}
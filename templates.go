@@ -0,0 +1,213 @@
+package graphqlgenerator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// TemplateFuncs are the functions available to output templates.
+var TemplateFuncs = template.FuncMap{
+	// quote returns s as a Go string literal.
+	"quote": strconv.Quote,
+}
+
+// kindTemplates names the template rendering each kind of definition.
+// Scalars have none; they produce no code.
+var kindTemplates = map[Kind]string{
+	KindObject:    "object",
+	KindInterface: "interface",
+	KindInput:     "input",
+	KindEnum:      "enum",
+	KindUnion:     "union",
+}
+
+// ObjectData is the data passed to the "object", "interface", "input",
+// "enum" and "union" templates.
+type ObjectData struct {
+	GqlModel
+	// GoName is the name of the generated Go variable, and GoInterfaces and
+	// GoTypes the names of those generated for Interfaces and Types.
+	GoName       string
+	GoInterfaces []string
+	GoTypes      []string
+	// Fields holds the data passed to the "field" or "inputField" template
+	// for each field.
+	Fields []FieldData
+	// Values holds the data for each value of an enum.
+	Values []EnumValueData
+	// Thunk is set when the StyleThunk output style is selected.
+	Thunk bool
+	// Cyclic is set when the fields of the type refer back to it, directly
+	// or through other types. Its config then has no fields; the "init" or
+	// "inputInit" template adds them.
+	Cyclic bool
+	// ResolveType is the Go expression for the ResolveType function of an
+	// interface or union when models are generated with the types, or ""
+	// otherwise. The "init" template sets it.
+	ResolveType string
+}
+
+// FieldData is the data passed to the "field" and "inputField" templates.
+type FieldData struct {
+	ModelVar
+	// Type is the Go expression for the field type, including list and
+	// non-null wrappers.
+	Type string
+	// Args holds the data passed to the "arg" template for each argument.
+	Args []ArgData
+	// DefaultValue is the Go expression for the default value of an input
+	// field, or "" if it has none.
+	DefaultValue string
+	// Deprecated is set when the field carries an honored @deprecated
+	// directive, giving DeprecationReason.
+	Deprecated        bool
+	DeprecationReason string
+	// Resolve is the name of the generated function resolving the field
+	// when WithResolvers is set, or "" if the field is read from the model.
+	Resolve string
+}
+
+// ArgData is the data passed to the "arg" template.
+type ArgData struct {
+	GqlArg
+	// Type is the Go expression for the argument type.
+	Type string
+	// DefaultValue is the Go expression for the default value, or "" if the
+	// argument has none.
+	DefaultValue string
+}
+
+// EnumValueData describes an enum value to the "enum" template.
+type EnumValueData struct {
+	EnumValue
+	// Value is the Go expression for the value graphql-go gives for it.
+	Value string
+	// Deprecated is set when the value carries an honored @deprecated
+	// directive, giving DeprecationReason.
+	Deprecated        bool
+	DeprecationReason string
+}
+
+// DefaultTemplates returns a new copy of the built in templates: "object",
+// "interface", "input", "enum" and "union" for each kind of definition,
+// "field" and "inputField" for fields, rendering the values of
+// "fieldConfig" and "inputFieldConfig", "arg" for arguments, "init" for the fields of
+// cyclic types and the ResolveType of interfaces and unions, and
+// "inputInit" for the fields of cyclic input types. Callers may
+// redefine any of them, for example with
+//
+//	DefaultTemplates().ParseFiles("field.tmpl")
+//
+// where field.tmpl contains {{define "field"}}...{{end}}.
+func DefaultTemplates() *template.Template {
+	return template.Must(template.New("object.tmpl").Funcs(TemplateFuncs).ParseFS(templateFiles, "templates/*.tmpl"))
+}
+
+// generateTemplates renders each definition of doc through the template of
+// its kind below the header comments, followed by the models.
+func (g *Generator) generateTemplates(doc *Document, packageName string, header []byte, models []ast.Decl) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(header)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	for _, obj := range doc.Types {
+		name, ok := kindTemplates[obj.Kind]
+		if !ok {
+			continue
+		}
+		data, err := g.objectData(obj)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.templates.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n")
+	}
+	for _, decl := range models {
+		fset := token.NewFileSet()
+		layoutNode(fset, decl)
+		if err := printer.Fprint(&buf, fset, decl); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n\n")
+	}
+	return formatSource(buf.Bytes())
+}
+
+func (g *Generator) objectData(obj GqlModel) (*ObjectData, error) {
+	data := &ObjectData{GqlModel: obj, GoName: g.goName(obj.Name), Thunk: g.style == StyleThunk, Cyclic: g.cyclic[obj.Name]}
+	for _, name := range obj.Interfaces {
+		data.GoInterfaces = append(data.GoInterfaces, g.goName(name))
+	}
+	for _, name := range obj.Types {
+		data.GoTypes = append(data.GoTypes, g.goName(name))
+	}
+	if resolve := g.resolveTypeExpr(obj); resolve != nil {
+		data.ResolveType = exprString(resolve)
+	}
+	for _, element := range obj.Variables {
+		typ, err := g.typeExpr(element.Tok, element.Lit)
+		if err != nil {
+			return nil, err
+		}
+		field := FieldData{ModelVar: element, Type: exprString(wrapType(typ, element.List, element.ItemRequired, element.Required))}
+		for _, arg := range element.Arg {
+			typ, err := g.typeExpr(arg.Tok, arg.Lit)
+			if err != nil {
+				return nil, err
+			}
+			argData := ArgData{GqlArg: arg, Type: exprString(wrapType(typ, arg.List, arg.ItemRequired, arg.Required))}
+			if arg.Default != "" {
+				value, err := g.defaultExpr(argType(&arg), arg.Default)
+				if err != nil {
+					return nil, fmt.Errorf("argument %s: %v", arg.Name, err)
+				}
+				argData.DefaultValue = exprString(value)
+			}
+			field.Args = append(field.Args, argData)
+		}
+		if g.hasResolver(&obj, &element) {
+			field.Resolve = resolveFunc(&obj, &element)
+		}
+		if element.Default != "" {
+			value, err := g.defaultExpr(fieldType(&element), element.Default)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", obj.Name, element.Name, err)
+			}
+			field.DefaultValue = exprString(value)
+		}
+		field.DeprecationReason, field.Deprecated, err = g.deprecationReason(element.Name, element.Directives)
+		if err != nil {
+			return nil, err
+		}
+		data.Fields = append(data.Fields, field)
+	}
+	for _, value := range obj.Values {
+		valueData := EnumValueData{EnumValue: value, Value: exprString(g.enumValueExpr(obj.Name, value.Name))}
+		var err error
+		valueData.DeprecationReason, valueData.Deprecated, err = g.deprecationReason(value.Name, value.Directives)
+		if err != nil {
+			return nil, err
+		}
+		data.Values = append(data.Values, valueData)
+	}
+	return data, nil
+}
+
+// exprString prints a Go expression, laid out as in the generated code.
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	fset := token.NewFileSet()
+	layoutNode(fset, expr)
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
@@ -0,0 +1,77 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+const templateSchema = `package models
+type Query {
+  timeseries: int
+  transactions: Transactions!
+}
+type Mutation {
+  performance(word: int = 100!, fish: Animal = {kind: FISH, tags: ["a", "b"]}): [PerformanceSummary]!
+}
+interface Node {
+  key: String!
+  parent: Node
+}
+type Transactions implements Node {
+  key: String!
+  parent: Node
+  amounts: [Float!]
+}
+input Animal {
+  kind: Kind!
+  legs: int = 4
+  tags: [String!]
+  young: [Animal!]
+}
+enum Kind {
+  FISH
+  BIRD @deprecated(reason: "Use FISH.")
+}
+union PerformanceSummary = Transactions`
+
+func Test_DefaultTemplates(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithStyle(StyleVar)},
+		{WithStyle(StyleThunk)},
+		{WithMode(ModeAll), WithNameSuffix("Type")},
+		{WithMode(ModeAll), WithNameSuffix("Type"), WithResolvers()},
+	} {
+		var want, got strings.Builder
+		if err := Generate(&want, strings.NewReader(templateSchema), opts...); err != nil {
+			t.Error(err)
+		}
+		err := Generate(&got, strings.NewReader(templateSchema), append(opts, WithTemplates(DefaultTemplates()))...)
+		if err != nil {
+			t.Error(err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("default templates generated\n%s\ninstead of\n%s", got.String(), want.String())
+		}
+	}
+}
+
+func Test_CustomTemplates(t *testing.T) {
+	templates := template.Must(DefaultTemplates().Parse(`{{define "field" -}}
+{{quote .Name}}: &graphql.Field{
+	Type: {{.Type}},
+	Resolve: resolve{{.Name}},
+},
+{{- end}}`))
+	var b strings.Builder
+	if err := Generate(&b, strings.NewReader(templateSchema), WithTemplates(templates)); err != nil {
+		t.Error(err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "Resolve: resolvetimeseries,") {
+		t.Errorf("custom field template was not used, returned %s", out)
+	}
+	if !strings.Contains(out, `var Mutation = graphql.NewObject(graphql.ObjectConfig{`) {
+		t.Errorf("default object template was not used, returned %s", out)
+	}
+}
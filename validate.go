@@ -0,0 +1,525 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationError is a rule of the GraphQL type system broken by a schema
+// that parses.
+type ValidationError struct {
+	File string // name of the schema file, if known
+	Pos  Pos
+	Msg  string
+}
+
+func (e *ValidationError) Error() string {
+	// Documents not read from schema text, such as introspection results,
+	// have no positions.
+	if e.Pos == (Pos{}) && e.File != "" {
+		return fmt.Sprintf("%s: %s", e.File, e.Msg)
+	}
+	if e.File != "" {
+		return fmt.Sprintf("%s:%s: %s", e.File, e.Pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// ValidationErrors lists the validation errors of a document in the order
+// of the definitions.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// builtinScalars are the names of the scalars every schema provides.
+var builtinScalars = []string{"Int", "Float", "String", "Boolean", "ID"}
+
+// Validate checks doc against the validation rules for type definitions
+// of the GraphQL specification and returns ValidationErrors listing every
+// rule broken, or nil.
+func (doc *Document) Validate() error {
+	return doc.validate(nil)
+}
+
+// validator collects the validation errors of a document.
+type validator struct {
+	// types holds the definition of each type name, by first occurrence.
+	types map[string]*GqlModel
+	// scalars holds type names the generator maps to Go expressions, which
+	// need not be declared.
+	scalars map[string]string
+	// cyclicDefaults holds the input fields whose default values have been
+	// reported as referring to themselves.
+	cyclicDefaults map[string]bool
+	errs           ValidationErrors
+}
+
+// validate is Validate, accepting references to any type in scalars.
+func (doc *Document) validate(scalars map[string]string) error {
+	v := &validator{types: make(map[string]*GqlModel), scalars: scalars, cyclicDefaults: make(map[string]bool)}
+	for i := range doc.Types {
+		obj := &doc.Types[i]
+		if first, ok := v.types[obj.Name]; ok {
+			v.errorf(obj, obj.Pos, "type %s is already defined at %s", obj.Name, first.Pos)
+			continue
+		}
+		v.types[obj.Name] = obj
+	}
+	for i := range doc.Types {
+		v.validateType(&doc.Types[i])
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// errorf records an error at pos in the file defining obj.
+func (v *validator) errorf(obj *GqlModel, pos Pos, format string, a ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{File: obj.File, Pos: pos, Msg: fmt.Sprintf(format, a...)})
+}
+
+// checkName reports a name reserved by the specification, of the definition
+// described by what.
+func (v *validator) checkName(obj *GqlModel, pos Pos, what string, name string) {
+	if strings.HasPrefix(name, "__") {
+		v.errorf(obj, pos, "%s must not begin with \"__\", which is reserved for introspection", what)
+	}
+}
+
+func (v *validator) validateType(obj *GqlModel) {
+	v.checkName(obj, obj.Pos, obj.Kind.String()+" "+obj.Name, obj.Name)
+	for _, name := range builtinScalars {
+		if strings.EqualFold(obj.Name, name) {
+			v.errorf(obj, obj.Pos, "%s %s redefines the built in scalar %s", obj.Kind, obj.Name, name)
+		}
+	}
+	switch obj.Kind {
+	case KindObject, KindInterface:
+		v.validateFields(obj)
+		v.validateImplements(obj)
+	case KindInput:
+		v.validateFields(obj)
+	case KindEnum:
+		v.validateEnum(obj)
+	case KindUnion:
+		v.validateUnion(obj)
+	}
+}
+
+// validateFields checks the fields of an object, interface or input type.
+func (v *validator) validateFields(obj *GqlModel) {
+	if len(obj.Variables) == 0 {
+		v.errorf(obj, obj.Pos, "%s %s must define one or more fields", obj.Kind, obj.Name)
+	}
+	input := obj.Kind == KindInput
+	seen := make(map[string]bool)
+	for _, field := range obj.Variables {
+		v.checkName(obj, field.Pos, "field "+obj.Name+"."+field.Name, field.Name)
+		if seen[field.Name] {
+			v.errorf(obj, field.Pos, "field %s.%s is defined more than once", obj.Name, field.Name)
+		}
+		seen[field.Name] = true
+		v.checkType(obj, field.Pos, "field "+obj.Name+"."+field.Name, field.Tok, field.Lit, input)
+		if input && len(field.Arg) > 0 {
+			v.errorf(obj, field.Pos, "input field %s.%s must not have arguments", obj.Name, field.Name)
+		}
+		if !input && field.Default != "" {
+			v.errorf(obj, field.Pos, "field %s.%s of %s %s must not have a default value", obj.Name, field.Name, obj.Kind, obj.Name)
+		} else if input {
+			v.checkDefault(obj, field.Pos, "field "+obj.Name+"."+field.Name, fieldType(&field), field.Default)
+			v.checkDefaultCycle(obj, &field)
+		}
+		seenArgs := make(map[string]bool)
+		for _, arg := range field.Arg {
+			what := "argument " + obj.Name + "." + field.Name + "(" + arg.Name + ":)"
+			v.checkName(obj, arg.Pos, what, arg.Name)
+			if seenArgs[arg.Name] {
+				v.errorf(obj, arg.Pos, "%s is defined more than once", what)
+			}
+			seenArgs[arg.Name] = true
+			v.checkType(obj, arg.Pos, what, arg.Tok, arg.Lit, true)
+			v.checkDefault(obj, arg.Pos, what, argType(&arg), arg.Default)
+		}
+	}
+}
+
+// checkType reports a reference to a type that is not defined, or that
+// cannot be used as an input type when input is set or as an output type
+// otherwise.
+func (v *validator) checkType(obj *GqlModel, pos Pos, what string, tok Token, lit string, input bool) {
+	if scalarName(tok) != "" {
+		return
+	}
+	ref, ok := v.types[lit]
+	if !ok {
+		if _, ok := v.scalars[lit]; !ok {
+			v.errorf(obj, pos, "%s has unknown type %s", what, lit)
+		}
+		return
+	}
+	switch {
+	case input && ref.Kind != KindScalar && ref.Kind != KindEnum && ref.Kind != KindInput:
+		v.errorf(obj, pos, "%s must have an input type, found %s %s", what, ref.Kind, lit)
+	case !input && ref.Kind == KindInput:
+		v.errorf(obj, pos, "%s must have an output type, found input %s", what, lit)
+	}
+}
+
+// checkDefault reports a default value that cannot be coerced to the type
+// of the argument or input field described by what.
+func (v *validator) checkDefault(obj *GqlModel, pos Pos, what string, typ gqlType, text string) {
+	if text == "" {
+		return
+	}
+	value, err := parseDefault(text)
+	if err != nil {
+		v.errorf(obj, pos, "%s has invalid default value %s: %v", what, text, err)
+		return
+	}
+	if msg := v.checkValue(typ, value); msg != "" {
+		v.errorf(obj, pos, "%s has invalid default value %s: %s", what, text, msg)
+	}
+}
+
+// checkDefaultCycle reports an input field of obj whose default value
+// refers back to it through the defaults that input coercion fills in for
+// omitted fields, as in input A { a: A = {} }, which would expand without
+// end.
+func (v *validator) checkDefaultCycle(obj *GqlModel, field *ModelVar) {
+	start := obj.Name + "." + field.Name
+	if field.Default == "" || v.cyclicDefaults[start] {
+		return
+	}
+	defaultValue, err := parseDefault(field.Default)
+	if err != nil {
+		return
+	}
+	// path lists the fields whose defaults are being expanded; visited
+	// also holds those expanded without reaching start.
+	path := []string{start}
+	visited := map[string]bool{start: true}
+	var refers func(typ gqlType, given *value) bool
+	refers = func(typ gqlType, given *value) bool {
+		if typ.list {
+			item := gqlType{tok: typ.tok, lit: typ.lit, required: typ.itemRequired}
+			if given.kind != listValue {
+				return refers(item, given)
+			}
+			for _, element := range given.list {
+				if refers(item, element) {
+					return true
+				}
+			}
+			return false
+		}
+		def, ok := v.types[typ.name()]
+		if !ok || def.Kind != KindInput || given.kind != objectValue {
+			return false
+		}
+		for i := range def.Variables {
+			f := &def.Variables[i]
+			if fieldValue := given.field(f.Name); fieldValue != nil {
+				if refers(fieldType(f), fieldValue) {
+					return true
+				}
+				continue
+			}
+			if f.Default == "" {
+				continue
+			}
+			name := def.Name + "." + f.Name
+			if name == start {
+				return true
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			fieldDefault, err := parseDefault(f.Default)
+			if err != nil {
+				continue
+			}
+			path = append(path, name)
+			if refers(fieldType(f), fieldDefault) {
+				return true
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	if !refers(fieldType(field), defaultValue) {
+		return
+	}
+	for _, name := range path {
+		v.cyclicDefaults[name] = true
+	}
+	if len(path) == 1 {
+		v.errorf(obj, field.Pos, "field %s has default value %s, which refers to itself", start, field.Default)
+		return
+	}
+	v.errorf(obj, field.Pos, "field %s has default value %s, which refers to itself through the default value of %s",
+		start, field.Default, strings.Join(path[1:], ", "))
+}
+
+// checkValue describes why value cannot be coerced to typ, following the
+// input coercion rules of the specification, or returns "" if it can.
+func (v *validator) checkValue(typ gqlType, value *value) string {
+	if value.kind == nullValue {
+		if typ.required {
+			return fmt.Sprintf("null for non-null type %s", typ)
+		}
+		return ""
+	}
+	if typ.list {
+		item := gqlType{tok: typ.tok, lit: typ.lit, required: typ.itemRequired}
+		if value.kind != listValue {
+			// A single value is coerced to a list of one.
+			return v.checkValue(item, value)
+		}
+		for _, element := range value.list {
+			if msg := v.checkValue(item, element); msg != "" {
+				return msg
+			}
+		}
+		return ""
+	}
+
+	name := typ.name()
+	switch typ.tok {
+	case INT:
+		if value.kind != intValue {
+			return fmt.Sprintf("Int cannot represent %s", value)
+		}
+		if _, err := strconv.ParseInt(value.lit, 10, 32); err != nil {
+			return fmt.Sprintf("Int cannot represent %s, which is out of range", value)
+		}
+		return ""
+	case FLOAT:
+		if value.kind != intValue && value.kind != floatValue {
+			return fmt.Sprintf("Float cannot represent %s", value)
+		}
+		return ""
+	case STRING:
+		if value.kind != stringValue {
+			return fmt.Sprintf("String cannot represent %s", value)
+		}
+		return ""
+	case BOOLEAN:
+		if value.kind != booleanValue {
+			return fmt.Sprintf("Boolean cannot represent %s", value)
+		}
+		return ""
+	case ID:
+		if value.kind != stringValue && value.kind != intValue {
+			return fmt.Sprintf("ID cannot represent %s", value)
+		}
+		return ""
+	}
+
+	def, ok := v.types[name]
+	if !ok {
+		// A mapped scalar, or an unknown type reported elsewhere.
+		return ""
+	}
+	switch def.Kind {
+	case KindEnum:
+		if value.kind != enumValue {
+			return fmt.Sprintf("enum %s cannot represent %s", name, value)
+		}
+		for _, enumValue := range def.Values {
+			if enumValue.Name == value.lit {
+				return ""
+			}
+		}
+		return fmt.Sprintf("enum %s has no value %s", name, value)
+	case KindInput:
+		if value.kind != objectValue {
+			return fmt.Sprintf("input %s cannot represent %s", name, value)
+		}
+		for _, field := range value.fields {
+			if def.Field(field.name) == nil {
+				return fmt.Sprintf("input %s has no field %s", name, field.name)
+			}
+		}
+		for _, field := range def.Variables {
+			given := value.field(field.Name)
+			if given == nil {
+				if field.Required && field.Default == "" {
+					return fmt.Sprintf("missing required field %s of input %s", field.Name, name)
+				}
+				continue
+			}
+			if msg := v.checkValue(fieldType(&field), given); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
+
+// validateImplements checks the interfaces an object or interface
+// implements. It must implement the interfaces they implement in turn and
+// define each of their fields with a compatible type and arguments.
+func (v *validator) validateImplements(obj *GqlModel) {
+	seen := make(map[string]bool)
+	for _, name := range obj.Interfaces {
+		if seen[name] {
+			v.errorf(obj, obj.Pos, "%s %s implements %s more than once", obj.Kind, obj.Name, name)
+			continue
+		}
+		seen[name] = true
+		iface, ok := v.types[name]
+		if !ok {
+			v.errorf(obj, obj.Pos, "%s %s implements unknown interface %s", obj.Kind, obj.Name, name)
+			continue
+		}
+		if iface.Kind != KindInterface {
+			v.errorf(obj, obj.Pos, "%s %s implements %s %s, which is not an interface", obj.Kind, obj.Name, iface.Kind, name)
+			continue
+		}
+		if iface == obj {
+			v.errorf(obj, obj.Pos, "interface %s must not implement itself", obj.Name)
+			continue
+		}
+		for _, inherited := range iface.Interfaces {
+			if !v.implements(obj, inherited) {
+				v.errorf(obj, obj.Pos, "%s %s must implement %s, which is implemented by interface %s", obj.Kind, obj.Name, inherited, name)
+			}
+		}
+		for _, ifaceField := range iface.Variables {
+			field := obj.Field(ifaceField.Name)
+			if field == nil {
+				v.errorf(obj, obj.Pos, "%s %s must define field %s of interface %s", obj.Kind, obj.Name, ifaceField.Name, name)
+				continue
+			}
+			v.validateImplementsField(obj, field, name, &ifaceField)
+		}
+	}
+}
+
+// validateImplementsField checks that field of obj implements the field of
+// the same name of the interface iface.
+func (v *validator) validateImplementsField(obj *GqlModel, field *ModelVar, iface string, ifaceField *ModelVar) {
+	if !v.isSubtype(fieldType(field), fieldType(ifaceField)) {
+		v.errorf(obj, field.Pos, "field %s.%s has type %s, which does not implement type %s of %s.%s",
+			obj.Name, field.Name, fieldType(field), fieldType(ifaceField), iface, ifaceField.Name)
+	}
+	for _, ifaceArg := range ifaceField.Arg {
+		arg := findArg(field.Arg, ifaceArg.Name)
+		if arg == nil {
+			v.errorf(obj, field.Pos, "field %s.%s must take argument %s of %s.%s",
+				obj.Name, field.Name, ifaceArg.Name, iface, ifaceField.Name)
+			continue
+		}
+		if argType(arg).String() != argType(&ifaceArg).String() {
+			v.errorf(obj, arg.Pos, "argument %s.%s(%s:) has type %s, but %s.%s takes %s",
+				obj.Name, field.Name, arg.Name, argType(arg), iface, ifaceField.Name, argType(&ifaceArg))
+		}
+	}
+	for _, arg := range field.Arg {
+		if arg.Required && arg.Default == "" && findArg(ifaceField.Arg, arg.Name) == nil {
+			v.errorf(obj, arg.Pos, "argument %s.%s(%s:) must be optional, since %s.%s does not take it",
+				obj.Name, field.Name, arg.Name, iface, ifaceField.Name)
+		}
+	}
+}
+
+// implements reports whether obj declares that it implements iface.
+func (v *validator) implements(obj *GqlModel, iface string) bool {
+	for _, name := range obj.Interfaces {
+		if name == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubtype reports whether a field of type typ may implement an interface
+// field of type super. Field types are covariant: a non-null type may
+// implement a nullable one, and an object type may implement an interface
+// it implements or a union it belongs to.
+func (v *validator) isSubtype(typ gqlType, super gqlType) bool {
+	if super.required && !typ.required {
+		return false
+	}
+	if typ.list != super.list {
+		return false
+	}
+	if typ.list && super.itemRequired && !typ.itemRequired {
+		return false
+	}
+	if typ.name() == super.name() {
+		return true
+	}
+	sub, ok := v.types[typ.name()]
+	if !ok || (sub.Kind != KindObject && sub.Kind != KindInterface) {
+		return false
+	}
+	if v.implements(sub, super.name()) {
+		return true
+	}
+	if union, ok := v.types[super.name()]; ok && union.Kind == KindUnion {
+		for _, member := range union.Types {
+			if member == typ.name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findArg returns the argument with the given name, or nil if there is none.
+func findArg(args []GqlArg, name string) *GqlArg {
+	for i := range args {
+		if args[i].Name == name {
+			return &args[i]
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateEnum(obj *GqlModel) {
+	if len(obj.Values) == 0 {
+		v.errorf(obj, obj.Pos, "enum %s must define one or more values", obj.Name)
+	}
+	seen := make(map[string]bool)
+	for _, value := range obj.Values {
+		v.checkName(obj, value.Pos, "enum value "+obj.Name+"."+value.Name, value.Name)
+		switch value.Name {
+		case "true", "false", "null":
+			v.errorf(obj, value.Pos, "enum value %s.%s is not allowed", obj.Name, value.Name)
+		}
+		if seen[value.Name] {
+			v.errorf(obj, value.Pos, "enum value %s.%s is defined more than once", obj.Name, value.Name)
+		}
+		seen[value.Name] = true
+	}
+}
+
+func (v *validator) validateUnion(obj *GqlModel) {
+	if len(obj.Types) == 0 {
+		v.errorf(obj, obj.Pos, "union %s must have one or more members", obj.Name)
+	}
+	seen := make(map[string]bool)
+	for _, name := range obj.Types {
+		if seen[name] {
+			v.errorf(obj, obj.Pos, "union %s includes %s more than once", obj.Name, name)
+			continue
+		}
+		seen[name] = true
+		member, ok := v.types[name]
+		if !ok {
+			v.errorf(obj, obj.Pos, "union %s includes unknown type %s", obj.Name, name)
+		} else if member.Kind != KindObject {
+			v.errorf(obj, obj.Pos, "union %s includes %s %s, which is not an object type", obj.Name, member.Kind, name)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package graphqlgenerator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Validate(t *testing.T) {
+	testString := `interface Node {
+  key: String!
+}
+type User implements Node {
+  key: String!
+  friends(first: Int = 10, filter: Filter): [User!]
+  role: Role
+  search: Result
+  joined: Date
+}
+input Filter {
+  role: Role = USER
+}
+enum Role {
+  ADMIN
+  USER
+}
+scalar Date
+union Result = User`
+	doc, err := NewParser(strings.NewReader(testString)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Errorf("Validate rejected a valid schema: %v", err)
+	}
+}
+
+func Test_ValidateErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{"type Query { a: int }\ntype Query { b: int }", "2:1: type Query is already defined at 1:1"},
+		{"type string { a: int }", "1:1: type string redefines the built in scalar String"},
+		{"type __Query { a: int }", `1:1: type __Query must not begin with "__"`},
+		{"type Query {}", "1:1: type Query must define one or more fields"},
+		{"type Query {\n  a: int\n  a: String\n}", "3:3: field Query.a is defined more than once"},
+		{"type Query { a: User }", "1:14: field Query.a has unknown type User"},
+		{"type Query { a(x: int, x: int): int }", "1:24: argument Query.a(x:) is defined more than once"},
+		{"type Query { a: F }\ninput F { b: int }", "1:14: field Query.a must have an output type, found input F"},
+		{"type Query { a(x: Query): int }", "1:16: argument Query.a(x:) must have an input type, found type Query"},
+		{"input F { b: Query }\ntype Query { a: int }", "1:11: field F.b must have an input type, found type Query"},
+		{"input F { b(x: int): int }", "1:11: input field F.b must not have arguments"},
+		{"type Query { a: int = 1 }", "1:14: field Query.a of type Query must not have a default value"},
+		{"type Query implements Node { a: int }", "1:1: type Query implements unknown interface Node"},
+		{"type Query implements Role { a: int }\nenum Role { A }", "1:1: type Query implements enum Role, which is not an interface"},
+		{"type Query implements Node { a: int }\ninterface Node { b: int }", "1:1: type Query must define field b of interface Node"},
+		{"enum Role {}", "1:1: enum Role must define one or more values"},
+		{"enum Role { A, A }", "1:16: enum value Role.A is defined more than once"},
+		{"enum Role { null }", "1:13: enum value Role.null is not allowed"},
+		{"union U = Q | R\ntype Q { a: int }\nenum R { A }", "1:1: union U includes enum R, which is not an object type"},
+		{"union U = Q", "1:1: union U includes unknown type Q"},
+	}
+	for _, test := range tests {
+		doc, err := ParseFile("schema.graphql", strings.NewReader(test.schema))
+		if err != nil {
+			t.Errorf("ParseFile(%q) returned %v", test.schema, err)
+			continue
+		}
+		err = doc.Validate()
+		if err == nil {
+			t.Errorf("Validate(%q) did not report %q", test.schema, test.want)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "schema.graphql:"+test.want) {
+			t.Errorf("Validate(%q) returned\n%v\ninstead of\n%s", test.schema, err, test.want)
+		}
+	}
+}
+
+func Test_GenerateValidates(t *testing.T) {
+	testString := `package models
+type Query {
+  created: DateTime
+  user: User
+}`
+	var b strings.Builder
+	err := Generate(&b, strings.NewReader(testString), WithScalar("DateTime", "graphql.DateTime"))
+	if err == nil {
+		t.Fatalf("Generate did not report the unknown type User, returned %s", b.String())
+	}
+	if err.Error() != "4:3: field Query.user has unknown type User" {
+		t.Errorf("Generate returned %v instead of only the unknown type User", err)
+	}
+}
+
+func Test_ValidateImplements(t *testing.T) {
+	valid := `interface Node { key: ID }
+interface Entity implements Node {
+  key: ID
+  owner(first: Int): Node
+  tags: [String]
+  result: Result
+  count(limit: Int): int
+}
+type User implements Node & Entity {
+  key: ID!
+  owner(first: Int, after: String, sort: Int! = 1): User!
+  tags: [String!]!
+  result: User
+  count(limit: int): Int
+}
+union Result = User`
+	doc, err := NewParser(strings.NewReader(valid)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Errorf("Validate rejected covariant field types: %v", err)
+	}
+
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{"interface Node { key: ID! }\ntype User implements Node { key: ID }",
+			"2:29: field User.key has type ID, which does not implement type ID! of Node.key"},
+		{"interface Node { keys: [ID!] }\ntype User implements Node { keys: [ID] }",
+			"2:29: field User.keys has type [ID], which does not implement type [ID!] of Node.keys"},
+		{"interface Node { key: [ID] }\ntype User implements Node { key: ID }",
+			"2:29: field User.key has type ID, which does not implement type [ID] of Node.key"},
+		{"interface Node { owner: Node }\ntype User implements Node { owner: Group }\ntype Group { a: int }",
+			"2:29: field User.owner has type Group, which does not implement type Node of Node.owner"},
+		{"interface Node { f(a: Int): int }\ntype User implements Node { f: int }",
+			"2:29: field User.f must take argument a of Node.f"},
+		{"interface Node { f(a: Int): int }\ntype User implements Node { f(a: Int!): int }",
+			"2:31: argument User.f(a:) has type Int!, but Node.f takes Int"},
+		{"interface Node { f: int }\ntype User implements Node { f(b: Int!): int }",
+			"2:31: argument User.f(b:) must be optional, since Node.f does not take it"},
+		{"interface Node { a: int }\ninterface Entity implements Node { a: int }\ntype User implements Entity { a: int }",
+			"3:1: type User must implement Node, which is implemented by interface Entity"},
+	}
+	for _, test := range tests {
+		doc, err := ParseFile("schema.graphql", strings.NewReader(test.schema))
+		if err != nil {
+			t.Errorf("ParseFile(%q) returned %v", test.schema, err)
+			continue
+		}
+		err = doc.Validate()
+		if err == nil {
+			t.Errorf("Validate(%q) did not report %q", test.schema, test.want)
+			continue
+		}
+		if err.Error() != "schema.graphql:"+test.want {
+			t.Errorf("Validate(%q) returned\n%v\ninstead of\n%s", test.schema, err, test.want)
+		}
+	}
+}
+
+func Test_ValidateDefaults(t *testing.T) {
+	types := "\ninput Filter { role: Role!, tags: [String], limit: Int = 3 }\nenum Role { ADMIN USER }\nscalar Date"
+	valid := []string{
+		"a: Int = -10",
+		"a: Float = 1",
+		"a: Float = 1.5e3",
+		"a: ID = 5",
+		"a: ID = \"x\"",
+		"a: [Int] = 1",
+		"a: [Int!]! = [1, 2]",
+		"a: String = null",
+		"a: Role = USER",
+		"a: Filter = {role: ADMIN, tags: \"x\"}",
+		"a: [Filter] = [{role: USER, limit: 1}]",
+		"a: Date = \"2020-01-01\"",
+	}
+	for _, arg := range valid {
+		schema := "type Query { f(" + arg + "): int }" + types
+		doc, err := NewParser(strings.NewReader(schema)).ParseDocument()
+		if err != nil {
+			t.Errorf("ParseDocument(%q) returned %v", arg, err)
+			continue
+		}
+		if err := doc.Validate(); err != nil {
+			t.Errorf("Validate rejected %q: %v", arg, err)
+		}
+	}
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{`a: Int = "100"`, `has invalid default value "100": Int cannot represent "100"`},
+		{`a: Int = 1.5`, `has invalid default value 1.5: Int cannot represent 1.5`},
+		{`a: Int = 3000000000`, `Int cannot represent 3000000000, which is out of range`},
+		{`a: String = 1`, `String cannot represent 1`},
+		{`a: Boolean = "true"`, `Boolean cannot represent "true"`},
+		{`a: Int! = null`, `null for non-null type Int!`},
+		{`a: [Int!] = [1, null]`, `null for non-null type Int!`},
+		{`a: Int = [1]`, `Int cannot represent [1]`},
+		{`a: Role = PURPLE`, `enum Role has no value PURPLE`},
+		{`a: Role = "USER"`, `enum Role cannot represent "USER"`},
+		{`a: Filter = {tags: "x"}`, `missing required field role of input Filter`},
+		{`a: Filter = {role: USER, size: 1}`, `input Filter has no field size`},
+		{`a: Filter = {role: USER, limit: "1"}`, `Int cannot represent "1"`},
+	}
+	for _, test := range tests {
+		schema := "type Query { f(" + test.arg + "): int }" + types
+		doc, err := NewParser(strings.NewReader(schema)).ParseDocument()
+		if err != nil {
+			t.Errorf("ParseDocument(%q) returned %v", test.arg, err)
+			continue
+		}
+		err = doc.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Validate(%q) returned %v, expected an error containing %q", test.arg, err, test.want)
+		}
+	}
+}
+
+func Test_ValidateDefaultCycles(t *testing.T) {
+	valid := `input A { a: A = {a: null}, b: B }
+input B { a: A = {}, list: [B!] = [] }
+type Query { f(x: A = {}, y: B = {a: null}): int }`
+	doc, err := NewParser(strings.NewReader(valid)).ParseDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Errorf("Validate rejected defaults that end: %v", err)
+	}
+
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{"input A { a: A = {} }\ntype Query { f(x: A = {}): int }",
+			"1:11: field A.a has default value {}, which refers to itself"},
+		{"input A { a: [A] = [{}] }",
+			"1:11: field A.a has default value [{}], which refers to itself"},
+		{"input A { b: B = {} }\ninput B { c: C = {c: {}} }\ninput C { a: A = {}, c: C }",
+			"1:11: field A.b has default value {}, which refers to itself through the default value of B.c, C.a"},
+	}
+	for _, test := range tests {
+		doc, err := ParseFile("schema.graphql", strings.NewReader(test.schema))
+		if err != nil {
+			t.Errorf("ParseFile(%q) returned %v", test.schema, err)
+			continue
+		}
+		err = doc.Validate()
+		if err == nil || err.Error() != "schema.graphql:"+test.want {
+			t.Errorf("Validate(%q) returned\n%v\ninstead of\n%s", test.schema, err, test.want)
+		}
+	}
+
+	if _, err := GenerateToString(strings.NewReader("package models\ninput A { a: A = {} }\ntype Query { f(x: A = {}): int }")); err == nil {
+		t.Errorf("GenerateToString did not report a default value referring to itself")
+	}
+}
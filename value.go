@@ -0,0 +1,240 @@
+package graphqlgenerator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// valueKind is the kind of a constant value in a schema.
+type valueKind int
+
+const (
+	intValue valueKind = iota
+	floatValue
+	stringValue
+	booleanValue
+	nullValue
+	enumValue
+	listValue
+	objectValue
+)
+
+// value is a constant value, such as the default of an argument. Lit holds
+// scalar literals as written, strings with their quotes.
+type value struct {
+	kind   valueKind
+	lit    string
+	list   []*value
+	fields []objectField
+}
+
+// objectField is a field of an input object value.
+type objectField struct {
+	name  string
+	value *value
+}
+
+// field returns the value of the named field, or nil if it is not given.
+func (v *value) field(name string) *value {
+	for _, field := range v.fields {
+		if field.name == name {
+			return field.value
+		}
+	}
+	return nil
+}
+
+// String returns the schema notation for the value.
+func (v *value) String() string {
+	switch v.kind {
+	case listValue:
+		items := make([]string, len(v.list))
+		for i, item := range v.list {
+			items[i] = item.String()
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case objectValue:
+		fields := make([]string, len(v.fields))
+		for i, field := range v.fields {
+			fields[i] = field.name + ": " + field.value.String()
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case stringValue:
+		// Block strings and escapes are written in one canonical form.
+		if s, err := unquote(v.lit); err == nil {
+			return quote(s)
+		}
+	}
+	return v.lit
+}
+
+// parseValue parses a constant value.
+func (p *Parser) parseValue() (*value, error) {
+	tok, lit := p.scanIgnoreWhitespace()
+	switch {
+	case tok == NUMBER:
+		if strings.ContainsAny(lit, ".eE") {
+			return &value{kind: floatValue, lit: lit}, nil
+		}
+		return &value{kind: intValue, lit: lit}, nil
+	case tok == IDENT && strings.HasPrefix(lit, `"`):
+		return &value{kind: stringValue, lit: lit}, nil
+	case tok == SQBRACKETOPEN:
+		list := &value{kind: listValue}
+		for {
+			if tok, _ = p.scanIgnoreWhitespace(); tok == SQBRACKETCLOSE {
+				return list, nil
+			} else if tok != COMMA {
+				p.unscan()
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list.list = append(list.list, item)
+			}
+		}
+	case tok == CURLBRACKETOPEN:
+		object := &value{kind: objectValue}
+		for {
+			tok, lit = p.scanIgnoreWhitespace()
+			if tok == CURLBRACKETCLOSE {
+				return object, nil
+			}
+			if tok == COMMA {
+				continue
+			}
+			if !isName(tok, lit) {
+				return nil, p.errorf("found %q, expected field name err 29", lit)
+			}
+			field := objectField{name: lit}
+			if tok, lit = p.scanIgnoreWhitespace(); tok != COLON {
+				return nil, p.errorf("found %q, expected ':' err 30", lit)
+			}
+			item, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			field.value = item
+			object.fields = append(object.fields, field)
+		}
+	case isName(tok, lit):
+		switch lit {
+		case "true", "false":
+			return &value{kind: booleanValue, lit: lit}, nil
+		case "null":
+			return &value{kind: nullValue, lit: lit}, nil
+		}
+		return &value{kind: enumValue, lit: lit}, nil
+	}
+	return nil, p.errorf("found %q, expected value err 9", lit)
+}
+
+// unquote returns the string denoted by a GraphQL string literal or block
+// string.
+func unquote(lit string) (string, error) {
+	if strings.HasPrefix(lit, `"""`) {
+		if len(lit) < 6 || !strings.HasSuffix(lit, `"""`) {
+			return "", fmt.Errorf("invalid string %s", lit)
+		}
+		return blockString(lit[3 : len(lit)-3]), nil
+	}
+	// GraphQL escapes are those of Go, except for \/.
+	var b strings.Builder
+	for i := 0; i < len(lit); i++ {
+		if lit[i] == '\\' && i+1 < len(lit) {
+			if lit[i+1] == '/' {
+				b.WriteByte('/')
+				i++
+				continue
+			}
+			b.WriteByte(lit[i])
+			i++
+		}
+		b.WriteByte(lit[i])
+	}
+	s, err := strconv.Unquote(b.String())
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", lit)
+	}
+	return s, nil
+}
+
+// blockString returns the value of a block string with the given raw
+// content: escaped quotes restored, the indentation common to all lines
+// but the first removed and leading and trailing blank lines dropped, as
+// the GraphQL specification describes.
+func blockString(raw string) string {
+	raw = strings.ReplaceAll(raw, `\"""`, `"""`)
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if n < len(line) && (indent < 0 || n < indent) {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quote returns s as a GraphQL string literal. Unlike strconv.Quote it
+// only uses the escapes GraphQL defines.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// parseDefault parses a value recorded by the parser, such as the Default
+// of an argument.
+func parseDefault(text string) (*value, error) {
+	p := NewParser(strings.NewReader(text))
+	v, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if tok, lit := p.scanIgnoreWhitespace(); tok != EOF {
+		return nil, fmt.Errorf("unexpected %q after value", lit)
+	}
+	return v, nil
+}
@@ -0,0 +1,62 @@
+package graphqlgenerator
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// fileState is what polling observes of a watched file.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFiles(paths []string) []fileState {
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			states[i] = fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return states
+}
+
+// Watch calls fn once, then again whenever any of the files at paths change,
+// until ctx is done. The files are polled every interval, so Watch works on
+// any platform and file system. A burst of changes, such as an editor saving
+// several files, leads to a single call once the files have been left alone
+// for debounce.
+func Watch(ctx context.Context, paths []string, interval time.Duration, debounce time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	watch(ctx, paths, ticker.C, debounce, fn)
+}
+
+// watch implements Watch, polling the files at each time received from
+// ticks.
+func watch(ctx context.Context, paths []string, ticks <-chan time.Time, debounce time.Duration, fn func()) {
+	fn()
+	last := statFiles(paths)
+	var changed time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticks:
+			states := statFiles(paths)
+			for i := range states {
+				if states[i] != last[i] {
+					last = states
+					changed = now
+					break
+				}
+			}
+			if !changed.IsZero() && now.Sub(changed) >= debounce {
+				changed = time.Time{}
+				fn()
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package graphqlgenerator
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_Watch(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeFile(t, dir, "schema.graphql", "type Query {\n  timeseries: int\n}\n")
+	calls := 0
+	ticks := make(chan time.Time)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watch(ctx, []string{schema}, ticks, 20*time.Millisecond, func() { calls++ })
+		close(done)
+	}()
+
+	// Sending a tick waits for watch to take it, and so for the previous
+	// tick to have been handled: each check below follows an extra tick at
+	// the same time, which changes nothing.
+	start := time.Now()
+	tick := func(after time.Duration) {
+		ticks <- start.Add(after)
+		ticks <- start.Add(after)
+	}
+	tick(0)
+	if calls != 1 {
+		t.Fatalf("Watch called fn %d times before any change, expected once", calls)
+	}
+
+	// A burst of writes leads to a single call, once the files have been
+	// left alone for the debounce interval.
+	for i := 0; i < 3; i++ {
+		writeFile(t, dir, "schema.graphql", "type Query {\n  total: int\n}\n"[:20+i])
+		os.Chtimes(schema, time.Now(), time.Now().Add(time.Duration(i)*time.Second))
+		tick(time.Duration(i+1) * time.Millisecond)
+	}
+	tick(10 * time.Millisecond)
+	if calls != 1 {
+		t.Errorf("Watch called fn before the changes settled")
+	}
+	tick(30 * time.Millisecond)
+	if calls != 2 {
+		t.Errorf("Watch called fn %d times after a burst of changes, expected 2", calls)
+	}
+	tick(time.Second)
+	if calls != 2 {
+		t.Errorf("Watch called fn again without further changes")
+	}
+
+	cancel()
+	<-done
+}